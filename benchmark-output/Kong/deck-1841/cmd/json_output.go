@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kong/go-database-reconciler/pkg/diff"
+	"github.com/spf13/cobra"
+)
+
+// jsonOutput accumulates the result of a sync for rendering with
+// --json-output, across potentially multiple performDiff calls against
+// different tags/selectors in the same run.
+var jsonOutput diff.JSONOutputObject
+
+// jsonSchemaVersionFlag lets operators pin the --json-output schema version
+// they've integrated against, so a future breaking change to the shape can
+// be negotiated instead of silently shipped.
+var jsonSchemaVersionFlag string
+
+// addJSONSchemaVersionFlag registers --json-schema-version on cmd, for sync
+// commands that support --json-output.
+func addJSONSchemaVersionFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&jsonSchemaVersionFlag, "json-schema-version", diff.CurrentJSONSchemaVersion,
+		"expected schema_version of --json-output; the command fails if the generated output doesn't match")
+}
+
+// resetJSONOutput re-initializes jsonOutput before a sync run, guaranteeing
+// every slice field (including the dropped_* ones) is non-nil so it
+// serializes as [] rather than null even when nothing changed.
+func resetJSONOutput() {
+	jsonOutput = diff.NewJSONOutputObject()
+}
+
+// marshalJSONOutput serializes jsonOutput and, when --json-schema-version
+// was set via addJSONSchemaVersionFlag, validates the result against it
+// with diff.ValidateJSONOutput, failing the sync command instead of
+// silently shipping a schema the caller didn't pin to. Sync commands that
+// register the flag should call this instead of json.Marshal(jsonOutput)
+// directly when rendering --json-output.
+func marshalJSONOutput() ([]byte, error) {
+	data, err := json.Marshal(jsonOutput)
+	if err != nil {
+		return nil, fmt.Errorf("marshal JSON output: %w", err)
+	}
+
+	if jsonSchemaVersionFlag == "" {
+		return data, nil
+	}
+	if err := diff.ValidateJSONOutput(data, jsonSchemaVersionFlag); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
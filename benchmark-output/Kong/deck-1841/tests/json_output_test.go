@@ -7,6 +7,33 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func TestMarshalJSONOutput_FailsOnSchemaVersionMismatch(t *testing.T) {
+	resetJSONOutput()
+	jsonSchemaVersionFlag = "0.9"
+	defer func() { jsonSchemaVersionFlag = "" }()
+
+	_, err := marshalJSONOutput()
+	assert.ErrorContains(t, err, "does not match expected 0.9")
+}
+
+func TestMarshalJSONOutput_SucceedsWhenSchemaVersionMatches(t *testing.T) {
+	resetJSONOutput()
+	jsonSchemaVersionFlag = diff.CurrentJSONSchemaVersion
+	defer func() { jsonSchemaVersionFlag = "" }()
+
+	data, err := marshalJSONOutput()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"schema_version":"`+diff.CurrentJSONSchemaVersion+`"`)
+}
+
+func TestMarshalJSONOutput_SkipsValidationWhenFlagUnset(t *testing.T) {
+	resetJSONOutput()
+	jsonSchemaVersionFlag = ""
+
+	_, err := marshalJSONOutput()
+	assert.NoError(t, err)
+}
+
 func TestJSONOutput_DroppedOperationsInitialization(t *testing.T) {
 	// Reset jsonOutput to simulate syncMain behavior
 	jsonOutput = diff.JSONOutputObject{}
@@ -47,7 +74,7 @@ func TestJSONOutput_EntityChangesWithDroppedOperations(t *testing.T) {
 		Updating: []diff.EntityState{
 			{Name: "route-1", Kind: "route"},
 		},
-		Deleting:         []diff.EntityState{},
+		Deleting: []diff.EntityState{},
 		DroppedCreations: []diff.EntityState{
 			{Name: "failed-service", Kind: "service"},
 		},
@@ -0,0 +1,47 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// CurrentJSONSchemaVersion is the SchemaVersion NewJSONOutputObject stamps
+// onto freshly created output. Bump it whenever the JSON shape changes in a
+// way consumers should negotiate against (e.g. renaming an EntityState
+// field, or adding per-entity Reason/Error for dropped ops).
+const CurrentJSONSchemaVersion = "1.1"
+
+// defaultSchemaURL documents where the schema for SchemaVersion lives.
+const defaultSchemaURL = "https://github.com/kong/go-database-reconciler/blob/main/pkg/diff/schema/v1.1.json"
+
+// withSchemaMetadata is applied by NewJSONOutputObject; kept as a separate
+// function (rather than inlined) so tests can assert on it in isolation.
+func withSchemaMetadata(o JSONOutputObject) JSONOutputObject {
+	o.SchemaVersion = CurrentJSONSchemaVersion
+	url := defaultSchemaURL
+	o.SchemaURL = &url
+	o.GeneratedAt = generatedAtFunc()
+	return o
+}
+
+// generatedAtFunc is a var so tests can stub out the clock.
+var generatedAtFunc = time.Now
+
+// ValidateJSONOutput parses data as a JSONOutputObject and checks that its
+// SchemaVersion matches version, so a consumer pinned to an older schema
+// can fail fast on an incompatible payload instead of silently misreading
+// renamed/removed fields.
+func ValidateJSONOutput(data []byte, version string) error {
+	var out JSONOutputObject
+	if err := json.Unmarshal(data, &out); err != nil {
+		return fmt.Errorf("parse JSON output: %w", err)
+	}
+	if out.SchemaVersion == "" {
+		return fmt.Errorf("JSON output has no schema_version; expected %s", version)
+	}
+	if out.SchemaVersion != version {
+		return fmt.Errorf("JSON output schema_version %s does not match expected %s", out.SchemaVersion, version)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJSONOutputObject_StampsCurrentSchemaVersion(t *testing.T) {
+	out := NewJSONOutputObject()
+
+	assert.Equal(t, CurrentJSONSchemaVersion, out.SchemaVersion)
+	assert.NotNil(t, out.SchemaURL)
+	assert.False(t, out.GeneratedAt.IsZero())
+}
+
+func TestJSONOutputObject_RoundTripPreservesDroppedKeys(t *testing.T) {
+	out := NewJSONOutputObject()
+
+	data, err := json.Marshal(out)
+	assert.NoError(t, err)
+
+	assert.Contains(t, string(data), `"dropped_creations":[]`)
+	assert.Contains(t, string(data), `"dropped_updates":[]`)
+	assert.Contains(t, string(data), `"dropped_deletions":[]`)
+}
+
+func TestValidateJSONOutput_RejectsMismatchedVersion(t *testing.T) {
+	out := NewJSONOutputObject()
+	data, err := json.Marshal(out)
+	assert.NoError(t, err)
+
+	err = ValidateJSONOutput(data, "2.0")
+	assert.Error(t, err)
+}
+
+func TestValidateJSONOutput_AcceptsMatchingVersion(t *testing.T) {
+	out := NewJSONOutputObject()
+	data, err := json.Marshal(out)
+	assert.NoError(t, err)
+
+	err = ValidateJSONOutput(data, CurrentJSONSchemaVersion)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,84 @@
+package diff
+
+import "fmt"
+
+// PlannedOp is a single create/update/delete operation Solve is asked to
+// apply, tagged with the entities (as "kind:name") it depends on having
+// already succeeded.
+type PlannedOp struct {
+	EntityState
+	DependsOn []string
+}
+
+// Applier applies a single planned operation against Kong, returning an
+// error if it was rejected.
+type Applier func(op PlannedOp) error
+
+// entityKey identifies an entity for dependency lookups, matching the
+// "kind:name" format callers use to populate PlannedOp.DependsOn.
+func entityKey(kind, name string) string {
+	return kind + ":" + name
+}
+
+// Solve applies creating, then updating, then deleting, in that order,
+// skipping (dropping) any operation whose DependsOn references an entity
+// that already failed or was itself dropped earlier in the run. Each
+// dropped EntityState carries a Reason explaining the root cause, so JSON
+// output can say e.g. "dependency service:foo failed to create" instead of
+// leaving callers to guess from a bare name/kind pair.
+func Solve(creating, updating, deleting []PlannedOp, apply Applier) (EntityChanges, Summary) {
+	changes := EntityChanges{
+		Creating:         []EntityState{},
+		Updating:         []EntityState{},
+		Deleting:         []EntityState{},
+		DroppedCreations: []EntityState{},
+		DroppedUpdates:   []EntityState{},
+		DroppedDeletions: []EntityState{},
+	}
+	var summary Summary
+	failedVerb := map[string]string{}
+
+	apply1 := func(verb string, ops []PlannedOp, applied *[]EntityState, dropped *[]EntityState, count *int32) {
+		for _, op := range ops {
+			if dep, blockedVerb, blocked := firstFailedDependency(op.DependsOn, failedVerb); blocked {
+				es := op.EntityState
+				es.Reason = fmt.Sprintf("dependency %s failed to %s", dep, blockedVerb)
+				es.DependsOn = op.DependsOn
+				*dropped = append(*dropped, es)
+				failedVerb[entityKey(es.Kind, es.Name)] = verb
+				summary.Dropped++
+				continue
+			}
+			if err := apply(op); err != nil {
+				es := op.EntityState
+				es.Reason = fmt.Sprintf("failed to %s: %s", verb, err)
+				es.Error = err.Error()
+				es.DependsOn = op.DependsOn
+				*dropped = append(*dropped, es)
+				failedVerb[entityKey(es.Kind, es.Name)] = verb
+				summary.Dropped++
+				continue
+			}
+			*applied = append(*applied, op.EntityState)
+			*count++
+		}
+	}
+
+	apply1("create", creating, &changes.Creating, &changes.DroppedCreations, &summary.Creating)
+	apply1("update", updating, &changes.Updating, &changes.DroppedUpdates, &summary.Updating)
+	apply1("delete", deleting, &changes.Deleting, &changes.DroppedDeletions, &summary.Deleting)
+
+	summary.Total = summary.Creating + summary.Updating + summary.Deleting
+	return changes, summary
+}
+
+// firstFailedDependency returns the first entry in dependsOn that's already
+// in failedVerb, along with the verb that failed for it.
+func firstFailedDependency(dependsOn []string, failedVerb map[string]string) (dep, verb string, blocked bool) {
+	for _, dep := range dependsOn {
+		if verb, ok := failedVerb[dep]; ok {
+			return dep, verb, true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,69 @@
+package diff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSolve_DropsDependentsOfFailedCreate(t *testing.T) {
+	creating := []PlannedOp{
+		{EntityState: EntityState{Name: "foo", Kind: "service"}},
+		{
+			EntityState: EntityState{Name: "foo-route", Kind: "route"},
+			DependsOn:   []string{"service:foo"},
+		},
+	}
+
+	changes, summary := Solve(creating, nil, nil, func(op PlannedOp) error {
+		if op.Kind == "service" && op.Name == "foo" {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	assert.Len(t, changes.Creating, 0)
+	assert.Len(t, changes.DroppedCreations, 2)
+	assert.Equal(t, "failed to create: connection refused", changes.DroppedCreations[0].Reason)
+	assert.Equal(t, "connection refused", changes.DroppedCreations[0].Error)
+	assert.Equal(t, "dependency service:foo failed to create", changes.DroppedCreations[1].Reason)
+	assert.Equal(t, int32(2), summary.Dropped)
+	assert.Equal(t, int32(0), summary.Creating)
+}
+
+func TestSolve_AppliesIndependentOpsAcrossVerbs(t *testing.T) {
+	creating := []PlannedOp{{EntityState: EntityState{Name: "svc", Kind: "service"}}}
+	updating := []PlannedOp{{EntityState: EntityState{Name: "route", Kind: "route"}}}
+	deleting := []PlannedOp{{EntityState: EntityState{Name: "consumer", Kind: "consumer"}}}
+
+	changes, summary := Solve(creating, updating, deleting, func(op PlannedOp) error {
+		return nil
+	})
+
+	assert.Len(t, changes.Creating, 1)
+	assert.Len(t, changes.Updating, 1)
+	assert.Len(t, changes.Deleting, 1)
+	assert.Equal(t, int32(0), summary.Dropped)
+	assert.Equal(t, int32(3), summary.Total)
+}
+
+func TestSolve_UnrelatedFailureDoesNotBlockIndependentOps(t *testing.T) {
+	creating := []PlannedOp{
+		{EntityState: EntityState{Name: "foo", Kind: "service"}},
+		{EntityState: EntityState{Name: "bar", Kind: "service"}},
+	}
+
+	changes, summary := Solve(creating, nil, nil, func(op PlannedOp) error {
+		if op.Name == "foo" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	assert.Len(t, changes.Creating, 1)
+	assert.Equal(t, "bar", changes.Creating[0].Name)
+	assert.Len(t, changes.DroppedCreations, 1)
+	assert.Equal(t, int32(1), summary.Dropped)
+	assert.Equal(t, int32(1), summary.Creating)
+}
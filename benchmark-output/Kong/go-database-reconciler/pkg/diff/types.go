@@ -0,0 +1,85 @@
+// Package diff computes the set of create/update/delete operations needed
+// to reconcile Kong's configuration with a desired state, and reports the
+// result in a form callers can render as a human summary or serialize as
+// JSON for CI consumption.
+package diff
+
+import "time"
+
+// EntityState describes a single entity affected by a sync. Reason, Error,
+// and DependsOn are only populated on entities that Solve dropped rather
+// than applied: Reason explains why in human terms (e.g. "dependency
+// service:foo failed to create"), Error carries the underlying error
+// message when the drop was caused by this entity's own apply failing
+// (rather than a dependency's), and DependsOn lists the entities (as
+// "kind:name") this operation depended on.
+type EntityState struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+
+	Reason    string   `json:"reason,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// EntityChanges groups the entities a sync created, updated, or deleted,
+// plus any it had to drop rather than apply (e.g. because a dependency
+// failed). Solve and NewJSONOutputObject initialize every slice, including
+// the Dropped* ones, to non-nil so they render as [] rather than null; a
+// zero-value EntityChanges has nil slices, which render as null.
+type EntityChanges struct {
+	Creating []EntityState `json:"creating"`
+	Updating []EntityState `json:"updating"`
+	Deleting []EntityState `json:"deleting"`
+
+	DroppedCreations []EntityState `json:"dropped_creations"`
+	DroppedUpdates   []EntityState `json:"dropped_updates"`
+	DroppedDeletions []EntityState `json:"dropped_deletions"`
+}
+
+// Summary holds the entity counts for a sync, as returned by Solve(). Dropped
+// counts entities across all three Dropped* slices, so a caller can tell at
+// a glance whether anything needs attention without summing the slices.
+type Summary struct {
+	Creating int32 `json:"creating"`
+	Updating int32 `json:"updating"`
+	Deleting int32 `json:"deleting"`
+	Dropped  int32 `json:"dropped"`
+	Total    int32 `json:"total"`
+}
+
+// JSONOutputObject is the top-level shape of `deck gateway sync --json-output`.
+type JSONOutputObject struct {
+	// SchemaVersion identifies the shape of this payload, so consumers can
+	// negotiate compatibility (via ValidateJSONOutput) across breaking
+	// changes instead of guessing from field presence.
+	SchemaVersion string `json:"schema_version,omitempty"`
+	// GeneratedAt is when this output was produced.
+	GeneratedAt time.Time `json:"generated_at,omitempty"`
+	// SchemaURL documents where SchemaVersion's schema is published.
+	SchemaURL *string `json:"schema_url,omitempty"`
+
+	Changes  EntityChanges `json:"changes"`
+	Summary  Summary       `json:"summary"`
+	Warnings []string      `json:"warnings"`
+	Errors   []string      `json:"errors"`
+}
+
+// NewJSONOutputObject returns a JSONOutputObject with every slice field
+// initialized to empty (never nil), so it always serializes dropped_* and
+// warnings/errors as `[]` rather than `null`, stamped with the current
+// schema version.
+func NewJSONOutputObject() JSONOutputObject {
+	return withSchemaMetadata(JSONOutputObject{
+		Changes: EntityChanges{
+			Creating:         []EntityState{},
+			Updating:         []EntityState{},
+			Deleting:         []EntityState{},
+			DroppedCreations: []EntityState{},
+			DroppedUpdates:   []EntityState{},
+			DroppedDeletions: []EntityState{},
+		},
+		Warnings: []string{},
+		Errors:   []string{},
+	})
+}
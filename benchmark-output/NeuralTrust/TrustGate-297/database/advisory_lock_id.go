@@ -0,0 +1,34 @@
+package database
+
+import (
+	"hash/crc64"
+)
+
+// advisoryLockSalt namespaces TrustGate's derived lock IDs so that another
+// application sharing the same Postgres cluster (and coincidentally picking
+// the same database/schema name) can never collide with us.
+const advisoryLockSalt = "trustgate"
+
+var crc64Table = crc64.MakeTable(crc64.ISO)
+
+// GenerateAdvisoryLockID derives a stable, signed 64-bit advisory lock ID
+// from a database name and any number of additional qualifiers (typically
+// the current schema). Inputs are joined with a null byte so that, e.g.,
+// database "a"+schema "bc" can never hash the same as database "ab"+schema
+// "c". The result is combined with a fixed application salt so TrustGate's
+// lock IDs don't collide with unrelated applications using the same naming
+// scheme.
+func GenerateAdvisoryLockID(databaseName string, additional ...string) (int64, error) {
+	buf := []byte(advisoryLockSalt)
+	buf = append(buf, 0)
+	buf = append(buf, databaseName...)
+	for _, s := range additional {
+		buf = append(buf, 0)
+		buf = append(buf, s...)
+	}
+
+	sum := crc64.Checksum(buf, crc64Table)
+	// Advisory lock IDs are bigint (signed 64-bit) in Postgres; mask off the
+	// sign bit so the checksum always produces a positive ID.
+	return int64(sum & 0x7FFFFFFFFFFFFFFF), nil
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAdvisoryLockID_StableForSamePair(t *testing.T) {
+	id1, err := GenerateAdvisoryLockID("trustgate_prod", "public")
+	assert.NoError(t, err)
+
+	id2, err := GenerateAdvisoryLockID("trustgate_prod", "public")
+	assert.NoError(t, err)
+
+	assert.Equal(t, id1, id2)
+	assert.Greater(t, id1, int64(0))
+}
+
+func TestGenerateAdvisoryLockID_DistinctForDifferentSchemas(t *testing.T) {
+	id1, err := GenerateAdvisoryLockID("trustgate_prod", "tenant_a")
+	assert.NoError(t, err)
+
+	id2, err := GenerateAdvisoryLockID("trustgate_prod", "tenant_b")
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, id1, id2)
+}
+
+func TestGenerateAdvisoryLockID_NoBoundaryCollision(t *testing.T) {
+	// "a"+"bc" must not hash the same as "ab"+"c": the null-byte separator
+	// is what prevents this.
+	id1, err := GenerateAdvisoryLockID("a", "bc")
+	assert.NoError(t, err)
+
+	id2, err := GenerateAdvisoryLockID("ab", "c")
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, id1, id2)
+}
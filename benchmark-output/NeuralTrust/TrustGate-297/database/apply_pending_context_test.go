@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// neverAcquiresLocker implements SessionLocker and tryLocker, always
+// reporting that the lock is held by someone else, so acquireLock's retry
+// loop runs until it gives up on the context or the configured timeout.
+type neverAcquiresLocker struct{}
+
+func (neverAcquiresLocker) Lock(db *gorm.DB) error            { return nil }
+func (neverAcquiresLocker) Unlock(db *gorm.DB) error          { return nil }
+func (neverAcquiresLocker) TryLock(db *gorm.DB) (bool, error) { return false, nil }
+
+func TestAcquireLock_ContextCancellationStopsRetrying(t *testing.T) {
+	m := &MigrationsManager{
+		locker:            neverAcquiresLocker{},
+		lockID:            new(int64),
+		lockTimeout:       time.Minute,
+		lockRetryInterval: 10 * time.Millisecond,
+		lockRetryMinDelay: 10 * time.Millisecond,
+		lockRetryMaxDelay: 20 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(15 * time.Millisecond)
+		cancel()
+	}()
+
+	err := m.acquireLock(ctx)
+
+	var cmErr *ConcurrentMigrationError
+	assert.True(t, errors.As(err, &cmErr))
+	assert.True(t, errors.Is(err, ErrLockAcquireTimeout))
+}
+
+func TestAcquireLock_DeadlineExceededReturnsLockAcquireTimeout(t *testing.T) {
+	m := &MigrationsManager{
+		locker:            neverAcquiresLocker{},
+		lockID:            new(int64),
+		lockTimeout:       20 * time.Millisecond,
+		lockRetryInterval: 5 * time.Millisecond,
+		lockRetryMinDelay: 5 * time.Millisecond,
+		lockRetryMaxDelay: 5 * time.Millisecond,
+	}
+
+	err := m.acquireLock(context.Background())
+
+	assert.True(t, errors.Is(err, ErrLockAcquireTimeout))
+}
+
+func TestAcquireLock_TryLockErrorIsNotLockAcquireTimeout(t *testing.T) {
+	connErr := errors.New("connection refused")
+	m := &MigrationsManager{
+		locker:            tryLockErrorLocker{err: connErr},
+		lockID:            new(int64),
+		lockTimeout:       time.Minute,
+		lockRetryInterval: 5 * time.Millisecond,
+		lockRetryMinDelay: 5 * time.Millisecond,
+		lockRetryMaxDelay: 5 * time.Millisecond,
+	}
+
+	err := m.acquireLock(context.Background())
+
+	assert.Equal(t, connErr, err)
+	assert.False(t, errors.Is(err, ErrLockAcquireTimeout))
+}
+
+// tryLockErrorLocker always fails TryLock with err, simulating a dropped
+// connection rather than the lock being held elsewhere.
+type tryLockErrorLocker struct{ err error }
+
+func (l tryLockErrorLocker) Lock(db *gorm.DB) error            { return l.err }
+func (l tryLockErrorLocker) Unlock(db *gorm.DB) error          { return nil }
+func (l tryLockErrorLocker) TryLock(db *gorm.DB) (bool, error) { return false, l.err }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errConcurrentMigration is the sentinel ConcurrentMigrationError wraps, so
+// callers can use errors.Is(err, ErrConcurrentMigration) without caring
+// about the specific lock ID or timeout that produced it.
+var errConcurrentMigration = errors.New("another instance is migrating")
+
+// ErrConcurrentMigration is the sentinel error identifying a
+// ConcurrentMigrationError. Use errors.Is(err, ErrConcurrentMigration) to
+// detect it regardless of the wrapping.
+var ErrConcurrentMigration = errConcurrentMigration
+
+// ErrLockAcquireTimeout is returned (wrapped in a ConcurrentMigrationError)
+// when acquireLock's retry loop ran out of time without acquiring the lock,
+// as opposed to TryLock itself returning an error (a connection failure or
+// similar, which is returned unwrapped so it isn't mistaken for "another
+// instance is migrating"). Use errors.Is(err, ErrLockAcquireTimeout) to
+// distinguish the two.
+var ErrLockAcquireTimeout = errors.New("timed out waiting to acquire migration lock")
+
+// ConcurrentMigrationError is returned by ApplyPending (and the other
+// lock-guarded MigrationsManager methods) when the configured lock could not
+// be acquired before the WithLockTimeout duration elapsed or the context was
+// canceled, meaning another instance is still holding it. Callers can use this to decide whether to exit cleanly
+// (another instance will finish the job) rather than treating it as a fatal
+// database error.
+type ConcurrentMigrationError struct {
+	LockID   int64
+	Attempts int
+}
+
+func (e *ConcurrentMigrationError) Error() string {
+	return fmt.Sprintf("concurrent migration: lock %d still held after %d attempts", e.LockID, e.Attempts)
+}
+
+func (e *ConcurrentMigrationError) Is(target error) bool {
+	return target == errConcurrentMigration || target == ErrLockAcquireTimeout
+}
+
+func (e *ConcurrentMigrationError) Unwrap() error {
+	return errConcurrentMigration
+}
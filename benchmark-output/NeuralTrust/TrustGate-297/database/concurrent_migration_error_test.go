@@ -0,0 +1,28 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentMigrationError_ErrorsIs(t *testing.T) {
+	err := &ConcurrentMigrationError{LockID: 42, Attempts: 5}
+
+	assert.True(t, errors.Is(err, ErrConcurrentMigration))
+	assert.Contains(t, err.Error(), "lock 42")
+	assert.Contains(t, err.Error(), "5 attempts")
+}
+
+func TestConcurrentMigrationError_DistinctFromOtherErrors(t *testing.T) {
+	err := &ConcurrentMigrationError{LockID: 1, Attempts: 1}
+
+	assert.False(t, errors.Is(err, errors.New("connection refused")))
+}
+
+func TestConcurrentMigrationError_ErrorsIsLockAcquireTimeout(t *testing.T) {
+	err := &ConcurrentMigrationError{LockID: 1, Attempts: 3}
+
+	assert.True(t, errors.Is(err, ErrLockAcquireTimeout))
+}
@@ -0,0 +1,79 @@
+package database
+
+import "time"
+
+// hooks bundles the optional lifecycle callbacks a caller can attach to a
+// MigrationsManager to emit metrics, traces, or structured logs without
+// forking this package.
+type hooks struct {
+	beforeLock         func()
+	afterLock          func()
+	beforeMigration    func(Migration)
+	afterMigration     func(Migration, time.Duration, error)
+	lockWaitCallback   func(attempt int, elapsed time.Duration)
+}
+
+// WithBeforeLock registers a callback invoked right before ApplyPending
+// attempts to acquire its lock. MigrateTo, Rollback and RollbackSteps do not
+// call it.
+func WithBeforeLock(fn func()) Option {
+	return func(m *MigrationsManager) { m.hooks.beforeLock = fn }
+}
+
+// WithAfterLock registers a callback invoked once ApplyPending holds the
+// lock. MigrateTo, Rollback and RollbackSteps do not call it.
+func WithAfterLock(fn func()) Option {
+	return func(m *MigrationsManager) { m.hooks.afterLock = fn }
+}
+
+// WithBeforeMigration registers a callback invoked before each pending
+// migration's Up function runs.
+func WithBeforeMigration(fn func(Migration)) Option {
+	return func(m *MigrationsManager) { m.hooks.beforeMigration = fn }
+}
+
+// WithAfterMigration registers a callback invoked after each migration
+// attempt, successful or not, with a duration and its error (if any). The
+// duration is how long Up took only for migrations run by ApplyPending's
+// main loop; the bootstrap migration, MigrateTo and rollbacks pass 0.
+func WithAfterMigration(fn func(Migration, time.Duration, error)) Option {
+	return func(m *MigrationsManager) { m.hooks.afterMigration = fn }
+}
+
+// WithLockWaitCallback registers a callback invoked on every retry while
+// ApplyPending is waiting for the lock, receiving the attempt number and
+// elapsed wait time. Unlike the attempt-number log lines emitted internally,
+// this fires on every attempt so callers can drive a gauge or span.
+func WithLockWaitCallback(fn func(attempt int, elapsed time.Duration)) Option {
+	return func(m *MigrationsManager) { m.hooks.lockWaitCallback = fn }
+}
+
+func (h hooks) runBeforeLock() {
+	if h.beforeLock != nil {
+		h.beforeLock()
+	}
+}
+
+func (h hooks) runAfterLock() {
+	if h.afterLock != nil {
+		h.afterLock()
+	}
+}
+
+func (h hooks) runBeforeMigration(mig Migration) {
+	if h.beforeMigration != nil {
+		h.beforeMigration(mig)
+	}
+}
+
+func (h hooks) runAfterMigration(mig Migration, d time.Duration, err error) {
+	if h.afterMigration != nil {
+		h.afterMigration(mig, d, err)
+	}
+}
+
+func (h hooks) runLockWaitCallback(attempt int, elapsed time.Duration) {
+	if h.lockWaitCallback != nil {
+		h.lockWaitCallback(attempt, elapsed)
+	}
+}
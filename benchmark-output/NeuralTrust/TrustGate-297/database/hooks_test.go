@@ -0,0 +1,42 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBeforeAfterMigrationHooks(t *testing.T) {
+	var before, after []string
+	var lastDuration time.Duration
+
+	m := NewMigrationsManager(nil,
+		WithBeforeMigration(func(mig Migration) { before = append(before, mig.ID) }),
+		WithAfterMigration(func(mig Migration, d time.Duration, err error) {
+			after = append(after, mig.ID)
+			lastDuration = d
+		}),
+	)
+
+	mig := Migration{ID: "20240099_hook_test", Name: "Hook Test"}
+	m.hooks.runBeforeMigration(mig)
+	m.hooks.runAfterMigration(mig, 5*time.Millisecond, nil)
+
+	assert.Equal(t, []string{"20240099_hook_test"}, before)
+	assert.Equal(t, []string{"20240099_hook_test"}, after)
+	assert.Equal(t, 5*time.Millisecond, lastDuration)
+}
+
+func TestWithLockWaitCallback(t *testing.T) {
+	var attempts []int
+
+	m := NewMigrationsManager(nil, WithLockWaitCallback(func(attempt int, elapsed time.Duration) {
+		attempts = append(attempts, attempt)
+	}))
+
+	m.hooks.runLockWaitCallback(1, 0)
+	m.hooks.runLockWaitCallback(2, time.Second)
+
+	assert.Equal(t, []int{1, 2}, attempts)
+}
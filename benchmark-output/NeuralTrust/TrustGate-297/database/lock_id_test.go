@@ -0,0 +1,30 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockID_ZeroBeforeApplyLockID(t *testing.T) {
+	m := NewMigrationsManager(nil)
+
+	assert.Equal(t, int64(0), m.LockID())
+}
+
+func TestLockID_ReturnsPinnedValueImmediately(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockID(987654321))
+
+	assert.Equal(t, int64(987654321), m.LockID())
+}
+
+func TestLockID_CachedAfterApplyLockID(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockID(42))
+
+	assert.NoError(t, m.applyLockID())
+	assert.Equal(t, int64(42), m.LockID())
+
+	// A second call must not re-derive or otherwise change the cached ID.
+	assert.NoError(t, m.applyLockID())
+	assert.Equal(t, int64(42), m.LockID())
+}
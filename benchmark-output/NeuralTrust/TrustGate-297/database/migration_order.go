@@ -0,0 +1,100 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrMigrationCycle is returned by orderedMigrationIDs when migrationsRegistry
+// contains a dependency cycle, naming the migrations still stuck with
+// unresolved dependencies once every runnable migration has been ordered.
+type ErrMigrationCycle struct {
+	IDs []string
+}
+
+func (e *ErrMigrationCycle) Error() string {
+	return fmt.Sprintf("migration dependency cycle involving: %s", strings.Join(e.IDs, ", "))
+}
+
+// ErrUnknownDependency is returned by orderedMigrationIDs when a migration's
+// DependsOn names an ID that was never registered.
+type ErrUnknownDependency struct {
+	Migration string
+	DependsOn string
+}
+
+func (e *ErrUnknownDependency) Error() string {
+	return fmt.Sprintf("migration %s depends on unregistered migration %s", e.Migration, e.DependsOn)
+}
+
+// orderedMigrationIDs topologically sorts migrationsRegistry by DependsOn
+// using Kahn's algorithm: each migration is a node, each DependsOn entry an
+// incoming edge. Among migrations with no unresolved dependencies at a given
+// step, it always picks the smallest ID next, so the result is deterministic
+// and stable regardless of map/registration order, and falls back to plain
+// ID order for migrations that declare no dependencies at all.
+func orderedMigrationIDs() ([]string, error) {
+	indegree := make(map[string]int, len(migrationsRegistry))
+	dependents := make(map[string][]string, len(migrationsRegistry))
+
+	for id := range migrationsRegistry {
+		indegree[id] = 0
+	}
+	for id, m := range migrationsRegistry {
+		for _, dep := range m.DependsOn {
+			if _, ok := migrationsRegistry[dep]; !ok {
+				return nil, &ErrUnknownDependency{Migration: id, DependsOn: dep}
+			}
+			indegree[id]++
+			dependents[dep] = append(dependents[dep], id)
+		}
+	}
+
+	var ready []string
+	for id, n := range indegree {
+		if n == 0 {
+			ready = append(ready, id)
+		}
+	}
+	sort.Strings(ready)
+
+	order := make([]string, 0, len(migrationsRegistry))
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		order = append(order, id)
+
+		freed := append([]string(nil), dependents[id]...)
+		sort.Strings(freed)
+		for _, dep := range freed {
+			indegree[dep]--
+			if indegree[dep] == 0 {
+				ready = insertSorted(ready, dep)
+			}
+		}
+	}
+
+	if len(order) != len(migrationsRegistry) {
+		var stuck []string
+		for id, n := range indegree {
+			if n > 0 {
+				stuck = append(stuck, id)
+			}
+		}
+		sort.Strings(stuck)
+		return nil, &ErrMigrationCycle{IDs: stuck}
+	}
+
+	return order, nil
+}
+
+// insertSorted inserts id into the already-sorted slice ready, keeping it
+// sorted, so Kahn's algorithm always pops the smallest ready ID next.
+func insertSorted(ready []string, id string) []string {
+	i := sort.SearchStrings(ready, id)
+	ready = append(ready, "")
+	copy(ready[i+1:], ready[i:])
+	ready[i] = id
+	return ready
+}
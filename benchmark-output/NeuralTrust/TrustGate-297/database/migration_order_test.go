@@ -0,0 +1,85 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withCleanRegistry(t *testing.T) func() {
+	t.Helper()
+	saved := migrationsRegistry
+	migrationsRegistry = map[string]Migration{}
+	return func() {
+		migrationsRegistry = saved
+	}
+}
+
+func indexOf(order []string, id string) int {
+	for i, v := range order {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestOrderedMigrationIDs_DependencyBeforeDependent(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{ID: "20240002_b", DependsOn: []string{"20240001_a"}})
+	RegisterMigration(Migration{ID: "20240001_a"})
+
+	order, err := orderedMigrationIDs()
+	assert.NoError(t, err)
+	assert.Less(t, indexOf(order, "20240001_a"), indexOf(order, "20240002_b"))
+}
+
+func TestOrderedMigrationIDs_DependentSortsFirstByIDButDependencyWins(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	// B's ID sorts before A's, but B depends on A, so A must still come
+	// first in the final order.
+	RegisterMigration(Migration{ID: "20240001_b", DependsOn: []string{"20240099_a"}})
+	RegisterMigration(Migration{ID: "20240099_a"})
+
+	order, err := orderedMigrationIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"20240099_a", "20240001_b"}, order)
+}
+
+func TestOrderedMigrationIDs_TiesBreakByIDAscending(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{ID: "c"})
+	RegisterMigration(Migration{ID: "a"})
+	RegisterMigration(Migration{ID: "b"})
+
+	order, err := orderedMigrationIDs()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+}
+
+func TestOrderedMigrationIDs_DetectsCycle(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{ID: "a", DependsOn: []string{"b"}})
+	RegisterMigration(Migration{ID: "b", DependsOn: []string{"a"}})
+
+	_, err := orderedMigrationIDs()
+	var cycleErr *ErrMigrationCycle
+	assert.ErrorAs(t, err, &cycleErr)
+	assert.ElementsMatch(t, []string{"a", "b"}, cycleErr.IDs)
+}
+
+func TestOrderedMigrationIDs_DetectsUnknownDependency(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{ID: "a", DependsOn: []string{"does_not_exist"}})
+
+	_, err := orderedMigrationIDs()
+	var depErr *ErrUnknownDependency
+	assert.ErrorAs(t, err, &depErr)
+	assert.Equal(t, "a", depErr.Migration)
+	assert.Equal(t, "does_not_exist", depErr.DependsOn)
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// bootstrapMigrationID is the ID of the migration that adds
+// migration_version's rolled_back_at, direction, and checksum columns. It
+// is special-cased by applyBootstrap: every other migration's
+// already-applied check reads those same columns, which doesn't work for
+// the one migration that creates them.
+const bootstrapMigrationID = "00000000_add_rolled_back_at"
+
+// init registers the bootstrap migration that adds migration_version's
+// rolled_back_at, direction, and checksum columns. Its ID sorts first (and
+// it declares no dependencies) so it always runs before any migration that
+// relies on RollbackSteps/Rollback's "mark rolled back, allow re-apply"
+// semantics or on the checksum verification in applyPending/rollbackOne,
+// both of which read and write all three columns. applyBootstrap already
+// guards against running this more than once, so Up/Down use plain ADD
+// COLUMN/DROP COLUMN rather than the IF NOT EXISTS/IF EXISTS qualifiers
+// Postgres supports but SQLite (used by this package's own tests) doesn't.
+func init() {
+	RegisterMigration(Migration{
+		ID:   bootstrapMigrationID,
+		Name: "Add rolled_back_at, direction, and checksum to migration_version",
+		Up: func(db *gorm.DB) error {
+			if err := db.Exec(`ALTER TABLE migration_version ADD COLUMN rolled_back_at TIMESTAMP`).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`ALTER TABLE migration_version ADD COLUMN direction TEXT NOT NULL DEFAULT 'up'`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`ALTER TABLE migration_version ADD COLUMN checksum TEXT NOT NULL DEFAULT ''`).Error
+		},
+		Down: func(db *gorm.DB) error {
+			if err := db.Exec(`ALTER TABLE migration_version DROP COLUMN checksum`).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`ALTER TABLE migration_version DROP COLUMN direction`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`ALTER TABLE migration_version DROP COLUMN rolled_back_at`).Error
+		},
+	})
+}
+
+// applyBootstrap runs the migration_version bootstrap migration ahead of
+// the generic checksum-based loop in applyPending/applyOne, whose
+// already-applied check is a "SELECT checksum ... WHERE direction = 'up'"
+// that only works once this migration's own Up has run. It uses a
+// column-agnostic existence probe instead, and records itself with a plain
+// insert that leans on the table's column defaults (direction 'up',
+// checksum '') rather than naming columns that may not exist yet.
+func (m *MigrationsManager) applyBootstrap() error {
+	migration, ok := migrationsRegistry[bootstrapMigrationID]
+	if !ok {
+		return nil
+	}
+
+	var id string
+	row := m.db.Table("migration_version").Select("id").Where("id = ?", migration.ID).Row()
+	switch err := row.Scan(&id); {
+	case err == nil:
+		return nil
+	case err == sql.ErrNoRows:
+		// not yet applied, fall through
+	default:
+		return fmt.Errorf("check migration %s: %w", migration.ID, err)
+	}
+
+	m.hooks.runBeforeMigration(migration)
+	upErr := migration.Up(m.db)
+	m.hooks.runAfterMigration(migration, 0, upErr)
+	if upErr != nil {
+		return fmt.Errorf("apply migration %s: %w", migration.ID, upErr)
+	}
+
+	if err := m.db.Exec(
+		"INSERT INTO migration_version (id, name) VALUES (?, ?)",
+		migration.ID, migration.Name,
+	).Error; err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,482 @@
+// Package database provides TrustGate's schema migration runner on top of
+// gorm, including the cross-instance locking needed to keep horizontally
+// scaled deployments from applying the same migration twice.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration describes a single schema change. ID must be unique and sortable
+// (TrustGate convention is a date-based prefix, e.g. "20240001_add_users").
+type Migration struct {
+	ID   string
+	Name string
+	Up   func(db *gorm.DB) error
+	// Down reverses Up. It is optional; migrations without one can still be
+	// applied, they just can't be targeted by Rollback/RollbackSteps.
+	Down func(db *gorm.DB) error
+	// DependsOn lists the IDs of migrations that must run before this one,
+	// for teams developing features in parallel whose real ordering
+	// constraints don't line up with alphabetical ID order. Leave empty to
+	// rely on ID order alone, as before.
+	DependsOn []string
+}
+
+// checksum returns a short, stable hash of the migration's ID and name,
+// persisted alongside each applied migration so a deploy that renames an
+// already-applied migration (same ID, different Name) is refused instead of
+// silently skipped. Up and Down are not part of the hash, so a change to a
+// migration's functions alone is not detected.
+func (m Migration) checksum() string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(m.ID))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(m.Name))
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+var migrationsRegistry = map[string]Migration{}
+
+// migrationsOrder mirrors orderedMigrationIDs' result after the most recent
+// RegisterMigration call, for callers that want the run order without
+// handling the cycle/unknown-dependency errors orderedMigrationIDs can
+// return. It's kept up to date on a best-effort basis: a registration that
+// leaves the registry in a cyclic or otherwise unresolvable state doesn't
+// clear it, it just stops being refreshed until the registry is fixed.
+var migrationsOrder []string
+
+// RegisterMigration adds a migration to the registry. It is typically called
+// from an init() function in the file that defines the migration, keeping
+// each migration's code next to its registration. The run order (by ID, or
+// by DependsOn where declared) is computed lazily by orderedMigrationIDs,
+// not here, since registration order across files/packages isn't
+// meaningful; migrationsOrder is refreshed from it as a convenience for
+// callers that want a plain slice.
+func RegisterMigration(m Migration) {
+	migrationsRegistry[m.ID] = m
+	if order, err := orderedMigrationIDs(); err == nil {
+		migrationsOrder = order
+	}
+}
+
+// LockStrategy selects how MigrationsManager coordinates ApplyPending across
+// multiple instances of the application.
+type LockStrategy string
+
+const (
+	// LockStrategyAdvisory uses a PostgreSQL advisory lock. This is the
+	// historical default but is unsafe behind connection poolers (e.g.
+	// PgBouncer) running in transaction pooling mode, since advisory locks
+	// are session-scoped and the session backing a given query can change
+	// between statements.
+	LockStrategyAdvisory LockStrategy = "advisory"
+	// LockStrategyTable uses a row in a dedicated schema_lock table, taken
+	// and released inside a single transaction. It works through any
+	// connection pooler because the lock lives in the database, not the
+	// session.
+	LockStrategyTable LockStrategy = "table"
+	// LockStrategyNone disables locking entirely. Only safe for
+	// single-instance deployments or local development.
+	LockStrategyNone LockStrategy = "none"
+)
+
+// SessionLocker coordinates exclusive access to ApplyPending across
+// potentially many TrustGate instances sharing one database.
+type SessionLocker interface {
+	Lock(db *gorm.DB) error
+	Unlock(db *gorm.DB) error
+}
+
+// noopLocker implements LockStrategyNone.
+type noopLocker struct{}
+
+func (noopLocker) Lock(db *gorm.DB) error   { return nil }
+func (noopLocker) Unlock(db *gorm.DB) error { return nil }
+
+// advisoryLockID is the historical, hardcoded lock ID. It is the initial
+// LockID of NewAdvisoryLocker and NewTableLocker; before locking,
+// MigrationsManager replaces an AdvisoryLocker's ID with one derived from
+// the current database and schema, unless WithLockID pins a value.
+const advisoryLockID = 1234567890
+
+// AdvisoryLocker takes a PostgreSQL advisory lock for the duration of
+// ApplyPending. It is the original locking behavior and remains the default
+// strategy for plain Postgres deployments that don't sit behind a pooler.
+type AdvisoryLocker struct {
+	LockID int64
+}
+
+// NewAdvisoryLocker returns an AdvisoryLocker using the historical, fixed
+// lock ID.
+func NewAdvisoryLocker() *AdvisoryLocker {
+	return &AdvisoryLocker{LockID: advisoryLockID}
+}
+
+func (l *AdvisoryLocker) Lock(db *gorm.DB) error {
+	if err := db.Exec("SELECT pg_advisory_lock(?)", l.LockID).Error; err != nil {
+		return fmt.Errorf("acquire migration advisory lock: %w", err)
+	}
+	return nil
+}
+
+// TryLock attempts to acquire the advisory lock without blocking, using
+// pg_try_advisory_lock. It is used by MigrationsManager's retry loop so a
+// stuck pod can fail fast instead of hanging indefinitely on Lock.
+func (l *AdvisoryLocker) TryLock(db *gorm.DB) (bool, error) {
+	var acquired bool
+	row := db.Raw("SELECT pg_try_advisory_lock(?)", l.LockID).Row()
+	if err := row.Scan(&acquired); err != nil {
+		return false, fmt.Errorf("acquire migration advisory lock: %w", err)
+	}
+	return acquired, nil
+}
+
+func (l *AdvisoryLocker) Unlock(db *gorm.DB) error {
+	if err := db.Exec("SELECT pg_advisory_unlock(?)", l.LockID).Error; err != nil {
+		return fmt.Errorf("release migration advisory lock: %w", err)
+	}
+	return nil
+}
+
+// MigrationsManager applies registered migrations in order, guarding the
+// whole run with a SessionLocker so two instances never race.
+type MigrationsManager struct {
+	db     *gorm.DB
+	locker SessionLocker
+	lockID *int64
+
+	lockTimeout       time.Duration
+	lockRetryInterval time.Duration
+	lockRetryMinDelay time.Duration
+	lockRetryMaxDelay time.Duration
+
+	hooks     hooks
+	lastError error
+}
+
+// Option configures a MigrationsManager.
+type Option func(*MigrationsManager)
+
+// WithLocker overrides the SessionLocker used to guard ApplyPending.
+func WithLocker(locker SessionLocker) Option {
+	return func(m *MigrationsManager) {
+		m.locker = locker
+	}
+}
+
+// WithLockStrategy selects a built-in SessionLocker by name. It is a
+// convenience over WithLocker for the common cases.
+func WithLockStrategy(strategy LockStrategy) Option {
+	return func(m *MigrationsManager) {
+		switch strategy {
+		case LockStrategyTable:
+			m.locker = NewTableLocker(nil)
+		case LockStrategyNone:
+			m.locker = noopLocker{}
+		default:
+			m.locker = NewAdvisoryLocker()
+		}
+	}
+}
+
+// WithTableLockStaleTTL sets StaleTTL on the manager's locker, if it is a
+// *TableLocker, so a crashed migrator's lock row can be reclaimed instead of
+// blocking every other instance forever. It is a no-op for other lock
+// strategies (e.g. AdvisoryLocker, which PostgreSQL itself releases when the
+// holding session disconnects). Apply it after WithLocker/WithLockStrategy.
+func WithTableLockStaleTTL(ttl time.Duration) Option {
+	return func(m *MigrationsManager) {
+		if tl, ok := m.locker.(*TableLocker); ok {
+			tl.StaleTTL = ttl
+		}
+	}
+}
+
+// WithLockID overrides the derived advisory/table lock ID with a fixed
+// value, restoring the pre-chunk0-2 behavior for deployments that already
+// coordinate on a known ID.
+func WithLockID(lockID int64) Option {
+	return func(m *MigrationsManager) {
+		m.lockID = &lockID
+	}
+}
+
+// WithLockTimeout bounds how long ApplyPending will retry acquiring the lock
+// before giving up with a ConcurrentMigrationError. The zero value (the
+// default) blocks indefinitely, matching historical behavior.
+func WithLockTimeout(d time.Duration) Option {
+	return func(m *MigrationsManager) {
+		m.lockTimeout = d
+	}
+}
+
+// WithLockRetryInterval sets the initial delay between pg_try_advisory_lock
+// attempts when a lock timeout is configured.
+func WithLockRetryInterval(d time.Duration) Option {
+	return func(m *MigrationsManager) {
+		m.lockRetryInterval = d
+	}
+}
+
+// WithLockRetryBackoff sets the exponential backoff bounds applied to
+// successive retry intervals. Jitter of up to 20% is added to each delay to
+// avoid a thundering herd of instances retrying in lockstep.
+func WithLockRetryBackoff(min, max time.Duration) Option {
+	return func(m *MigrationsManager) {
+		m.lockRetryMinDelay = min
+		m.lockRetryMaxDelay = max
+	}
+}
+
+// NewMigrationsManager creates a MigrationsManager bound to db. By default it
+// locks with AdvisoryLocker, matching historical behavior; pass
+// WithLockStrategy(LockStrategyTable) (or WithLocker) for PgBouncer /
+// non-Postgres deployments.
+func NewMigrationsManager(db *gorm.DB, opts ...Option) *MigrationsManager {
+	m := &MigrationsManager{
+		db:                db,
+		locker:            NewAdvisoryLocker(),
+		lockRetryInterval: 100 * time.Millisecond,
+		lockRetryMinDelay: 100 * time.Millisecond,
+		lockRetryMaxDelay: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ApplyPending runs every migration that isn't currently applied in
+// migration_version (never recorded, or rolled back since), in the order
+// orderedMigrationIDs returns (by ID, with DependsOn respected), under the
+// manager's lock.
+func (m *MigrationsManager) ApplyPending() error {
+	return m.ApplyPendingContext(context.Background())
+}
+
+// ApplyPendingContext is ApplyPending with a context that governs lock
+// acquisition: if ctx is canceled (or its deadline passes) before the lock
+// is acquired, acquireLock stops retrying and returns a
+// ConcurrentMigrationError. ctx is only observed on the retrying path, i.e.
+// when a lock timeout is configured and the locker supports TryLock; the
+// blocking Lock fallback ignores it. It does not interrupt migrations that
+// are already running.
+func (m *MigrationsManager) ApplyPendingContext(ctx context.Context) error {
+	err := m.applyPending(ctx)
+	m.lastError = err
+	return err
+}
+
+func (m *MigrationsManager) applyPending(ctx context.Context) error {
+	if err := m.applyLockID(); err != nil {
+		return err
+	}
+
+	m.hooks.runBeforeLock()
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	m.hooks.runAfterLock()
+	defer m.locker.Unlock(m.db)
+
+	if err := m.applyBootstrap(); err != nil {
+		return err
+	}
+
+	order, err := orderedMigrationIDs()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range order {
+		migration := migrationsRegistry[id]
+
+		var recordedChecksum string
+		row := m.db.Table("migration_version").Select("checksum").
+			Where("id = ? AND direction = 'up' AND rolled_back_at IS NULL", migration.ID).Row()
+		err := row.Scan(&recordedChecksum)
+		switch {
+		case err == nil:
+			if recordedChecksum != "" && recordedChecksum != migration.checksum() {
+				return fmt.Errorf("migration %s: recorded checksum %s does not match current body (%s); refusing to continue", migration.ID, recordedChecksum, migration.checksum())
+			}
+			continue
+		case err == sql.ErrNoRows:
+			// not yet applied, fall through
+		default:
+			return fmt.Errorf("check migration %s: %w", migration.ID, err)
+		}
+
+		m.hooks.runBeforeMigration(migration)
+		start := time.Now()
+		upErr := migration.Up(m.db)
+		m.hooks.runAfterMigration(migration, time.Since(start), upErr)
+		if upErr != nil {
+			return fmt.Errorf("apply migration %s: %w", migration.ID, upErr)
+		}
+
+		// A rolled-back migration still has its row (id is the primary key);
+		// drop it so the re-applied migration can be recorded.
+		if err := m.db.Exec(
+			"DELETE FROM migration_version WHERE id = ? AND rolled_back_at IS NOT NULL",
+			migration.ID,
+		).Error; err != nil {
+			return fmt.Errorf("record migration %s: %w", migration.ID, err)
+		}
+		if err := m.db.Exec(
+			"INSERT INTO migration_version (id, name, direction, checksum) VALUES (?, ?, 'up', ?)",
+			migration.ID, migration.Name, migration.checksum(),
+		).Error; err != nil {
+			return fmt.Errorf("record migration %s: %w", migration.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// tryLocker is implemented by lockers that support a non-blocking
+// acquisition attempt, letting ApplyPending retry with backoff instead of
+// hanging indefinitely on Lock.
+type tryLocker interface {
+	TryLock(db *gorm.DB) (bool, error)
+}
+
+// acquireLock takes the manager's lock, falling back to the locker's
+// blocking Lock when no timeout is configured (the historical behavior) or
+// the locker doesn't support TryLock. When a timeout is configured, it polls
+// TryLock with exponential backoff and jitter, returning a
+// ConcurrentMigrationError wrapping ErrLockAcquireTimeout once the timeout
+// elapses (or ctx is canceled) so a stuck Kubernetes rollout fails fast
+// instead of hanging. A TryLock error (e.g. a dropped connection) is
+// returned unwrapped, so callers can tell "couldn't reach the database" from
+// "another instance is migrating".
+func (m *MigrationsManager) acquireLock(ctx context.Context) error {
+	tl, ok := m.locker.(tryLocker)
+	if !ok || m.lockTimeout <= 0 {
+		return m.locker.Lock(m.db)
+	}
+
+	start := time.Now()
+	deadline := start.Add(m.lockTimeout)
+	delay := m.lockRetryInterval
+	attempt := 0
+
+	for {
+		attempt++
+		acquired, err := tl.TryLock(m.db)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+
+		m.hooks.runLockWaitCallback(attempt, time.Since(start))
+
+		// Log at the 1st, 2nd, 4th, 8th... attempt so a long wait is
+		// visible without spamming the logs every retry interval.
+		if attempt == 1 || attempt&(attempt-1) == 0 {
+			log.Printf("database: waiting for migration lock (attempt %d)", attempt)
+		}
+
+		if err := ctx.Err(); err != nil {
+			return &ConcurrentMigrationError{LockID: m.LockID(), Attempts: attempt}
+		}
+		if time.Now().Add(delay).After(deadline) {
+			return &ConcurrentMigrationError{LockID: m.LockID(), Attempts: attempt}
+		}
+
+		jitter := time.Duration(rand.Int63n(int64(delay) / 5 + 1))
+		select {
+		case <-ctx.Done():
+			return &ConcurrentMigrationError{LockID: m.LockID(), Attempts: attempt}
+		case <-time.After(delay + jitter):
+		}
+
+		delay *= 2
+		if delay > m.lockRetryMaxDelay {
+			delay = m.lockRetryMaxDelay
+		}
+		if delay < m.lockRetryMinDelay {
+			delay = m.lockRetryMinDelay
+		}
+	}
+}
+
+// LockID returns the advisory/table lock ID this manager is using, for
+// observability (logging, metrics labels) and tests. It is 0 until the first
+// ApplyPending/ApplyPendingContext/MigrateTo/Rollback call has run
+// applyLockID, since a derived ID requires a database round trip; after
+// that, the value is cached for the lifetime of the manager (it does not
+// change if current_database()/current_schema() would now answer
+// differently) unless it was pinned up front with WithLockID.
+func (m *MigrationsManager) LockID() int64 {
+	if m.lockID == nil {
+		return 0
+	}
+	return *m.lockID
+}
+
+// applyLockID derives this deployment's advisory lock ID, unless the caller
+// pinned one with WithLockID, and pushes it into the active locker.
+// Deriving it from current_database()/current_schema() means two tenants
+// running the same migrations in different schemas of the same cluster
+// don't serialize against each other on the old hardcoded 1234567890 ID.
+// ApplyPending/MigrateTo/RollbackSteps/Rollback all call it before taking
+// the lock or running any query, so an undeived ID with no database to derive it from is also the
+// earliest point to catch a manager built around a nil m.db and fail with
+// an error instead of letting one of those callers panic on its first real
+// query. A pinned lock ID never needs m.db at all, so that case is allowed
+// through regardless. current_database()/current_schema() is Postgres-only
+// SQL, so the derivation only runs for *AdvisoryLocker; TableLocker exists
+// specifically so non-Postgres backends and poolers like PgBouncer don't
+// need session-level Postgres features, and LockStrategyNone has no locker
+// to push an ID into and no lock contention to derive one for, so both
+// return immediately without a round trip once a database is confirmed
+// present.
+func (m *MigrationsManager) applyLockID() error {
+	lockID := m.lockID
+	if lockID == nil {
+		if m.db == nil {
+			return fmt.Errorf("migrations: no database configured")
+		}
+		if tl, ok := m.locker.(*TableLocker); ok {
+			id := tl.LockID
+			m.lockID = &id
+			return nil
+		}
+		if _, ok := m.locker.(*AdvisoryLocker); !ok {
+			return nil
+		}
+
+		var dbName, schema string
+		row := m.db.Raw("SELECT current_database(), current_schema()").Row()
+		if err := row.Scan(&dbName, &schema); err != nil {
+			return fmt.Errorf("resolve database/schema for advisory lock: %w", err)
+		}
+
+		id, err := GenerateAdvisoryLockID(dbName, schema)
+		if err != nil {
+			return fmt.Errorf("derive advisory lock id: %w", err)
+		}
+		lockID = &id
+		m.lockID = &id
+	}
+
+	switch locker := m.locker.(type) {
+	case *AdvisoryLocker:
+		locker.LockID = *lockID
+	case *TableLocker:
+		locker.LockID = *lockID
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Plan returns the ordered list of migrations ApplyPending would run,
+// without touching the database. Useful in CI to diff what a PR would apply
+// against what's already in production.
+func (m *MigrationsManager) Plan() ([]Migration, error) {
+	var appliedIDs []string
+	if err := m.db.Table("migration_version").Where("direction = 'up'").Pluck("id", &appliedIDs).Error; err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(appliedIDs))
+	for _, id := range appliedIDs {
+		applied[id] = true
+	}
+
+	order, err := orderedMigrationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, id := range order {
+		if !applied[id] {
+			pending = append(pending, migrationsRegistry[id])
+		}
+	}
+	return pending, nil
+}
+
+// MigrateTo applies pending migrations up to and including id, then stops,
+// even if later migrations are also pending. It acquires the same lock as
+// ApplyPending.
+func (m *MigrationsManager) MigrateTo(id string) error {
+	if _, ok := migrationsRegistry[id]; !ok {
+		return fmt.Errorf("migrate to %s: unknown migration", id)
+	}
+
+	if err := m.applyLockID(); err != nil {
+		return err
+	}
+	if err := m.acquireLock(context.Background()); err != nil {
+		return err
+	}
+	defer m.locker.Unlock(m.db)
+
+	if err := m.applyBootstrap(); err != nil {
+		return err
+	}
+
+	order, err := orderedMigrationIDs()
+	if err != nil {
+		return err
+	}
+
+	for _, migID := range order {
+		if err := m.applyOne(migrationsRegistry[migID]); err != nil {
+			return err
+		}
+		if migID == id {
+			break
+		}
+	}
+	return nil
+}
+
+// RollbackSteps reverses the last `steps` applied migrations, most recent
+// first, under the manager's lock. A migration without a Down function
+// aborts the rollback before it's reached, leaving everything already
+// rolled back in place.
+func (m *MigrationsManager) RollbackSteps(ctx context.Context, steps int) error {
+	if err := m.applyLockID(); err != nil {
+		return err
+	}
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	defer m.locker.Unlock(m.db)
+
+	var appliedIDs []string
+	if err := m.db.WithContext(ctx).Table("migration_version").
+		Where("direction = 'up' AND rolled_back_at IS NULL").
+		Order("applied_at DESC").
+		Limit(steps).
+		Pluck("id", &appliedIDs).Error; err != nil {
+		return fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	for _, id := range appliedIDs {
+		if err := m.rollbackOne(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rollback reverses applied migrations, most recent first, stopping once it
+// reaches (but not including) target, under the manager's lock. target must
+// itself be a currently applied migration; a migration without a Down
+// function aborts the rollback before it's reached, leaving everything
+// already rolled back in place.
+func (m *MigrationsManager) Rollback(ctx context.Context, target string) error {
+	if _, ok := migrationsRegistry[target]; !ok {
+		return fmt.Errorf("rollback to %s: unknown migration", target)
+	}
+
+	if err := m.applyLockID(); err != nil {
+		return err
+	}
+	if err := m.acquireLock(ctx); err != nil {
+		return err
+	}
+	defer m.locker.Unlock(m.db)
+
+	var appliedIDs []string
+	if err := m.db.WithContext(ctx).Table("migration_version").
+		Where("direction = 'up' AND rolled_back_at IS NULL").
+		Order("applied_at DESC").
+		Pluck("id", &appliedIDs).Error; err != nil {
+		return fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	found := false
+	for _, id := range appliedIDs {
+		if id == target {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("rollback to %s: migration is not currently applied", target)
+	}
+
+	for _, id := range appliedIDs {
+		if id == target {
+			return nil
+		}
+		if err := m.rollbackOne(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollbackOne reverse-applies a single previously-applied migration:
+// running Down, then marking its migration_version row rolled back (direction
+// 'down', rolled_back_at set) in place. It updates the existing row rather
+// than inserting a separate audit row, since id is the table's primary key
+// and a migration can only ever have one current row.
+func (m *MigrationsManager) rollbackOne(id string) error {
+	migration, ok := migrationsRegistry[id]
+	if !ok {
+		return fmt.Errorf("rollback %s: migration no longer registered", id)
+	}
+	if migration.Down == nil {
+		return fmt.Errorf("rollback %s: migration has no Down function", id)
+	}
+
+	m.hooks.runBeforeMigration(migration)
+	if err := migration.Down(m.db); err != nil {
+		m.hooks.runAfterMigration(migration, 0, err)
+		return fmt.Errorf("rollback migration %s: %w", id, err)
+	}
+	m.hooks.runAfterMigration(migration, 0, nil)
+
+	if err := m.db.Exec(
+		"UPDATE migration_version SET direction = 'down', rolled_back_at = CURRENT_TIMESTAMP, checksum = ? WHERE id = ? AND direction = 'up' AND rolled_back_at IS NULL",
+		migration.checksum(), id,
+	).Error; err != nil {
+		return fmt.Errorf("mark %s rolled back: %w", id, err)
+	}
+	return nil
+}
+
+// applyOne applies a single migration if it hasn't already been recorded (or
+// was rolled back since), sharing the checksum-verification logic used by
+// ApplyPending.
+func (m *MigrationsManager) applyOne(migration Migration) error {
+	var recordedChecksum string
+	row := m.db.Table("migration_version").Select("checksum").
+		Where("id = ? AND direction = 'up' AND rolled_back_at IS NULL", migration.ID).Row()
+	switch err := row.Scan(&recordedChecksum); {
+	case err == nil:
+		if recordedChecksum != "" && recordedChecksum != migration.checksum() {
+			return fmt.Errorf("migration %s: recorded checksum does not match current body; refusing to continue", migration.ID)
+		}
+		return nil
+	case err == sql.ErrNoRows:
+		// not yet applied, fall through
+	default:
+		return fmt.Errorf("check migration %s: %w", migration.ID, err)
+	}
+
+	m.hooks.runBeforeMigration(migration)
+	if err := migration.Up(m.db); err != nil {
+		m.hooks.runAfterMigration(migration, 0, err)
+		return fmt.Errorf("apply migration %s: %w", migration.ID, err)
+	}
+	m.hooks.runAfterMigration(migration, 0, nil)
+
+	// A rolled-back migration still has its row (id is the primary key);
+	// drop it so the re-applied migration can be recorded.
+	if err := m.db.Exec(
+		"DELETE FROM migration_version WHERE id = ? AND rolled_back_at IS NOT NULL",
+		migration.ID,
+	).Error; err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.ID, err)
+	}
+	return m.db.Exec(
+		"INSERT INTO migration_version (id, name, direction, checksum) VALUES (?, ?, 'up', ?)",
+		migration.ID, migration.Name, migration.checksum(),
+	).Error
+}
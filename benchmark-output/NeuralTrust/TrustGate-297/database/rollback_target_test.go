@@ -0,0 +1,44 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRollbackOne_ErrorsWithoutDownFunction(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{ID: "20240300_no_down", Name: "No Down"})
+
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+	err := m.rollbackOne("20240300_no_down")
+
+	assert.ErrorContains(t, err, "has no Down function")
+}
+
+func TestRollbackOne_ErrorsForUnregisteredMigration(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+
+	err := m.rollbackOne("never_registered")
+	assert.ErrorContains(t, err, "no longer registered")
+}
+
+func TestRollback_RejectsUnknownTargetBeforeAcquiringLock(t *testing.T) {
+	defer withCleanRegistry(t)()
+
+	RegisterMigration(Migration{
+		ID: "20240301_a",
+		Up: func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error {
+			return nil
+		},
+	})
+
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+
+	err := m.Rollback(context.Background(), "20240301_unknown")
+	assert.ErrorContains(t, err, "unknown migration")
+}
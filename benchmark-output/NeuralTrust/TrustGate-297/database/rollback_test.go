@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestRegisterMigration_AddsToRegistry(t *testing.T) {
+	RegisterMigration(Migration{
+		ID:   "20240200_plan_test",
+		Name: "Plan Test Migration",
+		Up:   func(db *gorm.DB) error { return nil },
+	})
+
+	assert.Contains(t, migrationsRegistry, "20240200_plan_test")
+}
+
+func TestMigration_ChecksumStableForSameBody(t *testing.T) {
+	m := Migration{ID: "20240201_checksum_test", Name: "Checksum Test"}
+
+	assert.Equal(t, m.checksum(), m.checksum())
+}
+
+func TestMigration_ChecksumDiffersWhenNameChanges(t *testing.T) {
+	a := Migration{ID: "20240202_checksum_test", Name: "Original"}
+	b := Migration{ID: "20240202_checksum_test", Name: "Renamed"}
+
+	assert.NotEqual(t, a.checksum(), b.checksum())
+}
+
+func TestRollbackSteps_ErrorsWithoutDatabase(t *testing.T) {
+	manager := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+
+	// applyLockID rejects a nil *gorm.DB before RollbackSteps ever reaches
+	// a real query; this asserts it returns an error instead of panicking.
+	err := manager.RollbackSteps(context.Background(), 1)
+	assert.Error(t, err)
+}
+
+func TestRollback_ErrorsForUnknownTarget(t *testing.T) {
+	manager := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+
+	err := manager.Rollback(context.Background(), "does_not_exist")
+	assert.Error(t, err)
+}
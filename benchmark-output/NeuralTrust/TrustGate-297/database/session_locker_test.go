@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMigrationsManager_DefaultsToAdvisoryLocker(t *testing.T) {
+	m := NewMigrationsManager(nil)
+
+	locker, ok := m.locker.(*AdvisoryLocker)
+	assert.True(t, ok, "default locker should be *AdvisoryLocker")
+	assert.Equal(t, int64(1234567890), locker.LockID)
+}
+
+func TestWithLockStrategy_Table(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyTable))
+
+	_, ok := m.locker.(*TableLocker)
+	assert.True(t, ok, "WithLockStrategy(LockStrategyTable) should install a *TableLocker")
+}
+
+func TestWithLockStrategy_None(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyNone))
+
+	_, ok := m.locker.(noopLocker)
+	assert.True(t, ok, "WithLockStrategy(LockStrategyNone) should install a noopLocker")
+}
+
+func TestWithLocker_CustomImplementation(t *testing.T) {
+	custom := NewTableLocker(nil)
+	m := NewMigrationsManager(nil, WithLocker(custom))
+
+	assert.Same(t, custom, m.locker)
+}
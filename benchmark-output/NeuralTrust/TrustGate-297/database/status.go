@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// ManagerStatus is a point-in-time snapshot of a MigrationsManager, suitable
+// for exposing on /healthz or /readyz during a rollout so operators can see
+// whether a pod is still waiting on the migration lock.
+type ManagerStatus struct {
+	Applied    []string
+	Pending    []string
+	LockHolder string
+	LastError  error
+}
+
+// HealthStatus reports which migrations have been applied, which are still
+// pending, who currently holds the lock (only resolvable for the advisory
+// strategy, via pg_locks), and the error (if any) from the most recent
+// ApplyPending call. It's the coarse, /healthz-shaped view; use Status for
+// a per-migration breakdown before rolling back.
+func (m *MigrationsManager) HealthStatus(ctx context.Context) (ManagerStatus, error) {
+	status := ManagerStatus{
+		LastError: m.lastError,
+	}
+
+	var appliedIDs []string
+	if err := m.db.WithContext(ctx).Table("migration_version").
+		Where("direction = 'up' AND rolled_back_at IS NULL").
+		Pluck("id", &appliedIDs).Error; err != nil {
+		return status, err
+	}
+
+	applied := make(map[string]bool, len(appliedIDs))
+	for _, id := range appliedIDs {
+		applied[id] = true
+	}
+
+	order, err := orderedMigrationIDs()
+	if err != nil {
+		return status, err
+	}
+
+	for _, id := range order {
+		if applied[id] {
+			status.Applied = append(status.Applied, id)
+		} else {
+			status.Pending = append(status.Pending, id)
+		}
+	}
+
+	if _, ok := m.locker.(*AdvisoryLocker); ok && m.lockID != nil {
+		status.LockHolder = m.advisoryLockHolder()
+	}
+
+	return status, nil
+}
+
+// MigrationStatus describes a single registered migration's applied state,
+// for operators inspecting what Rollback/RollbackSteps would act on before
+// running it.
+type MigrationStatus struct {
+	ID        string
+	Name      string
+	AppliedAt *time.Time
+	Pending   bool
+}
+
+// Status returns one MigrationStatus per registered migration, in run
+// order, so an operator can see exactly what's applied (and when) before
+// choosing a Rollback target.
+func (m *MigrationsManager) Status(ctx context.Context) ([]MigrationStatus, error) {
+	order, err := orderedMigrationIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	type appliedRow struct {
+		ID        string
+		AppliedAt time.Time
+	}
+	var rows []appliedRow
+	if err := m.db.WithContext(ctx).Table("migration_version").
+		Select("id, applied_at").
+		Where("direction = 'up' AND rolled_back_at IS NULL").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	appliedAt := make(map[string]time.Time, len(rows))
+	for _, r := range rows {
+		appliedAt[r.ID] = r.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(order))
+	for _, id := range order {
+		migration := migrationsRegistry[id]
+		s := MigrationStatus{ID: migration.ID, Name: migration.Name}
+		if at, ok := appliedAt[id]; ok {
+			t := at
+			s.AppliedAt = &t
+		} else {
+			s.Pending = true
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// advisoryLockHolder queries pg_locks for the PID currently holding our
+// advisory lock ID, returning "" if nobody does (or the query fails, e.g.
+// on a non-Postgres backend).
+func (m *MigrationsManager) advisoryLockHolder() string {
+	var pid string
+	row := m.db.Raw(
+		"SELECT pid FROM pg_locks WHERE locktype = 'advisory' AND objid = ? AND granted LIMIT 1",
+		*m.lockID,
+	).Row()
+	if err := row.Scan(&pid); err != nil {
+		return ""
+	}
+	return pid
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TableLocker implements SessionLocker using a row in a schema_lock table
+// instead of a PostgreSQL advisory lock. Because the lock lives in the
+// database rather than on the session, it works correctly through
+// connection poolers such as PgBouncer running in transaction pooling mode,
+// and on non-Postgres backends that gorm supports.
+type TableLocker struct {
+	// LockID identifies the lock row, mirroring AdvisoryLocker's LockID so
+	// the two strategies can be swapped without reshuffling unrelated state.
+	LockID int64
+	// PollInterval is the initial delay between acquisition attempts.
+	PollInterval time.Duration
+	// MaxPollInterval caps the backoff applied to PollInterval.
+	MaxPollInterval time.Duration
+	// StaleTTL is how long a lock row may sit unrenewed before a later Lock
+	// call is allowed to reclaim it, on the assumption that whatever held it
+	// crashed without running Unlock. Zero disables reclamation, so a stuck
+	// lock blocks forever (the historical, conservative behavior).
+	StaleTTL time.Duration
+
+	// holder identifies this process for the row's holder column, so an
+	// operator inspecting schema_lock can tell which instance/PID is (or
+	// was) holding the lock.
+	holder string
+
+	tx *gorm.DB
+}
+
+// NewTableLocker returns a TableLocker with TrustGate's default polling
+// behavior and no stale-lock reclamation. Pass nil to use the same LockID as
+// AdvisoryLocker.
+func NewTableLocker(lockID *int64) *TableLocker {
+	id := int64(advisoryLockID)
+	if lockID != nil {
+		id = *lockID
+	}
+	return &TableLocker{
+		LockID:          id,
+		PollInterval:    100 * time.Millisecond,
+		MaxPollInterval: 2 * time.Second,
+		holder:          processHolder(),
+	}
+}
+
+// processHolder identifies the current process as "hostname:pid", so a
+// crashed holder's row can be attributed when reclaimed.
+func processHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
+
+// schemaLockTableSQL creates the schema_lock table if it doesn't already
+// exist. It is safe to run concurrently from multiple instances.
+const schemaLockTableSQL = `
+CREATE TABLE IF NOT EXISTS schema_lock (
+	lock_id BIGINT PRIMARY KEY,
+	holder TEXT NOT NULL,
+	acquired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+func (l *TableLocker) Lock(db *gorm.DB) error {
+	interval := l.PollInterval
+	for {
+		acquired, err := l.TryLock(db)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+
+		time.Sleep(interval)
+		interval *= 2
+		if interval > l.MaxPollInterval {
+			interval = l.MaxPollInterval
+		}
+	}
+}
+
+// TryLock attempts to claim the schema_lock row for LockID without
+// blocking, so it composes with MigrationsManager.acquireLock's
+// timeout/cancellation polling the same way AdvisoryLocker.TryLock does.
+// A false, nil result means the row is currently held by another instance;
+// the caller is expected to retry.
+func (l *TableLocker) TryLock(db *gorm.DB) (bool, error) {
+	if err := db.Exec(schemaLockTableSQL).Error; err != nil {
+		return false, errors.New("create schema_lock table: " + err.Error())
+	}
+	if l.holder == "" {
+		l.holder = processHolder()
+	}
+
+	tx := db.Begin()
+	err := tx.Exec("INSERT INTO schema_lock (lock_id, holder) VALUES (?, ?)", l.LockID, l.holder).Error
+	if err == nil {
+		// Row inserted: we hold the lock. Leave the transaction open;
+		// Unlock commits it (deleting the row first), releasing the
+		// row-level lock we're implicitly holding until then.
+		l.tx = tx
+		return true, nil
+	}
+	tx.Rollback()
+
+	if l.StaleTTL > 0 {
+		if err := l.reclaimIfStale(db); err != nil {
+			return false, err
+		}
+	}
+
+	return false, nil
+}
+
+// reclaimIfStale deletes the existing schema_lock row for LockID if it was
+// acquired more than StaleTTL ago, on the assumption that whatever held it
+// crashed without running Unlock. It is a best-effort DELETE scoped by
+// acquired_at, so a holder that's still alive but merely slow simply has its
+// still-fresh row survive the WHERE clause untouched.
+func (l *TableLocker) reclaimIfStale(db *gorm.DB) error {
+	cutoff := time.Now().Add(-l.StaleTTL)
+	result := db.Exec(
+		"DELETE FROM schema_lock WHERE lock_id = ? AND acquired_at < ?",
+		l.LockID, cutoff,
+	)
+	if result.Error != nil {
+		return fmt.Errorf("reclaim stale schema_lock row: %w", result.Error)
+	}
+	return nil
+}
+
+func (l *TableLocker) Unlock(db *gorm.DB) error {
+	if l.tx == nil {
+		return nil
+	}
+	tx := l.tx
+	l.tx = nil
+
+	if err := tx.Exec("DELETE FROM schema_lock WHERE lock_id = ?", l.LockID).Error; err != nil {
+		tx.Rollback()
+		return errors.New("release schema_lock row: " + err.Error())
+	}
+	return tx.Commit().Error
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestNewTableLocker_SetsDefaultsAndHolder(t *testing.T) {
+	locker := NewTableLocker(nil)
+
+	assert.Equal(t, int64(advisoryLockID), locker.LockID)
+	assert.Equal(t, time.Duration(0), locker.StaleTTL)
+	assert.NotEmpty(t, locker.holder, "holder should default to hostname:pid")
+}
+
+func TestNewTableLocker_UsesProvidedLockID(t *testing.T) {
+	id := int64(42)
+	locker := NewTableLocker(&id)
+
+	assert.Equal(t, id, locker.LockID)
+}
+
+func TestWithTableLockStaleTTL_AppliesOnlyToTableLocker(t *testing.T) {
+	m := NewMigrationsManager(nil, WithLockStrategy(LockStrategyTable), WithTableLockStaleTTL(30*time.Second))
+
+	tl, ok := m.locker.(*TableLocker)
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, tl.StaleTTL)
+}
+
+func TestWithTableLockStaleTTL_NoOpForAdvisoryLocker(t *testing.T) {
+	m := NewMigrationsManager(nil, WithTableLockStaleTTL(30*time.Second))
+
+	_, ok := m.locker.(*AdvisoryLocker)
+	assert.True(t, ok, "default locker should remain an AdvisoryLocker")
+}
+
+func TestProcessHolder_FormatsHostnameAndPID(t *testing.T) {
+	holder := processHolder()
+	assert.Contains(t, holder, ":")
+}
+
+func TestTableLocker_ImplementsTryLocker(t *testing.T) {
+	var _ tryLocker = (*TableLocker)(nil)
+}
+
+// TestAcquireLock_TableLockerRespectsTimeout verifies that a manager using
+// LockStrategyTable against an already-held lock row fails fast on its
+// configured lockTimeout instead of polling TableLocker.Lock forever, since
+// TableLocker.Lock has no way to observe a context deadline on its own.
+func TestAcquireLock_TableLockerRespectsTimeout(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file:table_locker_timeout?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite db: %v", err)
+	}
+
+	holder := NewTableLocker(nil)
+	if err := holder.Lock(db); err != nil {
+		t.Fatalf("failed to seed held lock: %v", err)
+	}
+	defer holder.Unlock(db)
+
+	m := &MigrationsManager{
+		db:                db,
+		locker:            NewTableLocker(nil),
+		lockID:            new(int64),
+		lockTimeout:       50 * time.Millisecond,
+		lockRetryInterval: 5 * time.Millisecond,
+		lockRetryMinDelay: 5 * time.Millisecond,
+		lockRetryMaxDelay: 5 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	lockErr := m.acquireLock(ctx)
+	elapsed := time.Since(start)
+
+	assert.True(t, errors.Is(lockErr, ErrLockAcquireTimeout))
+	assert.Less(t, elapsed, 500*time.Millisecond, "acquireLock should fail fast on lockTimeout rather than blocking on TableLocker.Lock")
+}
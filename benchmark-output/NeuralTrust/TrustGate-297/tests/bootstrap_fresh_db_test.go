@@ -0,0 +1,36 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestApplyPending_BootstrapsFreshMigrationVersionTable verifies that
+// ApplyPending succeeds against a migration_version table shaped like the
+// one advisory_lock_presence_test.go creates (id, name, applied_at only),
+// with no advisory lock in the way. Before the fix, the bootstrap
+// migration's own already-applied check read its not-yet-added
+// direction/checksum columns and failed with "no such column: checksum"
+// on every fresh database.
+func TestApplyPending_BootstrapsFreshMigrationVersionTable(t *testing.T) {
+	db := setupTestDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+
+	_, err = sqlDB.Exec(`
+		CREATE TABLE IF NOT EXISTS migration_version (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`)
+	if err != nil {
+		t.Fatalf("failed to create migration_version table: %v", err)
+	}
+
+	manager := NewMigrationsManager(db, WithLockStrategy(LockStrategyNone))
+	assert.NoError(t, manager.ApplyPending())
+}
@@ -0,0 +1,171 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// stampAppliedAt backdates id's applied_at so tests can control rollback
+// ordering deterministically instead of relying on CURRENT_TIMESTAMP's
+// one-second resolution, which two migrations applied in the same test can
+// easily tie on.
+func stampAppliedAt(t *testing.T, sqlDB *sql.DB, id string, at time.Time) {
+	t.Helper()
+	if _, err := sqlDB.Exec(`UPDATE migration_version SET applied_at = ? WHERE id = ?`, at, id); err != nil {
+		t.Fatalf("failed to stamp applied_at for %s: %v", id, err)
+	}
+}
+
+// withCleanRegistryKeepingBootstrap resets migrationsRegistry to just the
+// bootstrap migration for the duration of the calling test and restores
+// the original contents afterwards, so ApplyPending/MigrateTo only ever
+// see the migrations this test registers itself rather than tripping
+// over unrelated migrations (including always-failing ones) left behind
+// by other tests in the package. Unlike withCleanRegistry (used by
+// migration_order_test.go, which never touches the database), the
+// bootstrap migration is kept here since applyBootstrap is a no-op
+// without it, which would leave migration_version missing the columns
+// this package's own code expects.
+func withCleanRegistryKeepingBootstrap(t *testing.T) {
+	t.Helper()
+	saved := migrationsRegistry
+	clean := map[string]Migration{}
+	if bootstrap, ok := saved[bootstrapMigrationID]; ok {
+		clean[bootstrapMigrationID] = bootstrap
+	}
+	migrationsRegistry = clean
+	t.Cleanup(func() {
+		migrationsRegistry = saved
+	})
+}
+
+// TestRollback_AppliesAllThenRollsBackToTarget registers three migrations,
+// applies all of them, then rolls back to the first one and verifies the
+// later two ran Down in reverse order under the lock, per the scenario
+// this feature was built against.
+func TestRollback_AppliesAllThenRollsBackToTarget(t *testing.T) {
+	withCleanRegistryKeepingBootstrap(t)
+
+	db, err := gorm.Open(sqlite.Open("file:chunk35_rollback_round_trip?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite db: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	if _, err := sqlDB.Exec(`
+		CREATE TABLE IF NOT EXISTS migration_version (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		t.Fatalf("failed to create migration_version table: %v", err)
+	}
+
+	var downOrder []string
+	RegisterMigration(Migration{
+		ID:   "20240500_rt_a",
+		Name: "Round Trip A",
+		Up:   func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error { downOrder = append(downOrder, "a"); return nil },
+	})
+	RegisterMigration(Migration{
+		ID:   "20240501_rt_b",
+		Name: "Round Trip B",
+		Up:   func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error { downOrder = append(downOrder, "b"); return nil },
+	})
+	RegisterMigration(Migration{
+		ID:   "20240502_rt_c",
+		Name: "Round Trip C",
+		Up:   func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error { downOrder = append(downOrder, "c"); return nil },
+	})
+
+	manager := NewMigrationsManager(db, WithLockStrategy(LockStrategyNone))
+	if err := manager.ApplyPending(); err != nil {
+		t.Fatalf("ApplyPending failed: %v", err)
+	}
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	stampAppliedAt(t, sqlDB, bootstrapMigrationID, base)
+	stampAppliedAt(t, sqlDB, "20240500_rt_a", base.Add(1*time.Minute))
+	stampAppliedAt(t, sqlDB, "20240501_rt_b", base.Add(2*time.Minute))
+	stampAppliedAt(t, sqlDB, "20240502_rt_c", base.Add(3*time.Minute))
+
+	err = manager.Rollback(context.Background(), "20240500_rt_a")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"c", "b"}, downOrder, "expected B and C to roll back most-recent-first")
+
+	assert.True(t, isMigrationApplied(db, "20240500_rt_a"), "target migration should remain applied")
+	assert.False(t, isMigrationApplied(db, "20240501_rt_b"), "B should be rolled back")
+	assert.False(t, isMigrationApplied(db, "20240502_rt_c"), "C should be rolled back")
+}
+
+// TestRollback_ErrorsWhenTargetIsNotCurrentlyApplied verifies Rollback
+// refuses to run when target is registered but was never applied (or was
+// already rolled back), instead of silently rolling back every applied
+// migration because the scan loop never finds a match.
+func TestRollback_ErrorsWhenTargetIsNotCurrentlyApplied(t *testing.T) {
+	withCleanRegistryKeepingBootstrap(t)
+
+	db, err := gorm.Open(sqlite.Open("file:chunk35_rollback_not_applied?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to open sqlite db: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get sql.DB: %v", err)
+	}
+	if _, err := sqlDB.Exec(`
+		CREATE TABLE IF NOT EXISTS migration_version (
+			id TEXT PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`); err != nil {
+		t.Fatalf("failed to create migration_version table: %v", err)
+	}
+
+	var downRan bool
+	RegisterMigration(Migration{
+		ID:   "20240510_na_applied",
+		Name: "Not Applied Applied",
+		Up:   func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error { downRan = true; return nil },
+	})
+	RegisterMigration(Migration{
+		ID:   "20240511_na_never_applied",
+		Name: "Not Applied Never Applied",
+		Up:   func(db *gorm.DB) error { return nil },
+		Down: func(db *gorm.DB) error { downRan = true; return nil },
+	})
+
+	manager := NewMigrationsManager(db, WithLockStrategy(LockStrategyNone))
+	if err := manager.MigrateTo("20240510_na_applied"); err != nil {
+		t.Fatalf("MigrateTo failed: %v", err)
+	}
+
+	err = manager.Rollback(context.Background(), "20240511_na_never_applied")
+	assert.ErrorContains(t, err, "is not currently applied")
+	assert.False(t, downRan, "Rollback must not run any Down function when target was never applied")
+}
+
+// isMigrationApplied reports whether id has a currently-applied (not rolled
+// back) 'up' row in migration_version.
+func isMigrationApplied(db *gorm.DB, id string) bool {
+	var count int64
+	db.Table("migration_version").
+		Where("id = ? AND direction = 'up' AND rolled_back_at IS NULL", id).
+		Count(&count)
+	return count > 0
+}
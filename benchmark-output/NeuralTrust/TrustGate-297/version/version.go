@@ -0,0 +1,112 @@
+// Package version exposes TrustGate's build metadata, populated via
+// -ldflags at build time and falling back to runtime/debug.ReadBuildInfo()
+// for `go run`/`go install` builds where ldflags aren't set.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Version is TrustGate's release version. Bump this on every release.
+const Version = "1.13.1"
+
+// AppName is the name reported in Info.
+const AppName = "TrustGate"
+
+// These are normally set via:
+//
+//	-ldflags "-X github.com/NeuralTrust/TrustGate/version.gitCommit=$(git rev-parse HEAD) ..."
+//
+// and fall back to runtime/debug.ReadBuildInfo() when left empty, so a
+// plain `go build`/`go run` still reports something useful.
+var (
+	gitCommit    string
+	gitTreeState string
+	buildDate    string
+)
+
+// Info is TrustGate's build metadata, in a form that's stable to marshal as
+// JSON/YAML for a /version endpoint or `trustgate version` command.
+type Info struct {
+	AppName      string `json:"appName" yaml:"appName"`
+	Version      string `json:"version" yaml:"version"`
+	GitCommit    string `json:"gitCommit" yaml:"gitCommit"`
+	GitTreeState string `json:"gitTreeState" yaml:"gitTreeState"`
+	BuildDate    string `json:"buildDate" yaml:"buildDate"`
+	GoVersion    string `json:"goVersion" yaml:"goVersion"`
+	Platform     string `json:"platform" yaml:"platform"`
+	Compiler     string `json:"compiler" yaml:"compiler"`
+}
+
+// String returns a single-line human-readable summary, in the style of
+// kubectl/helm's version subcommands.
+func (i Info) String() string {
+	return fmt.Sprintf("%s %s (commit %s, built %s, %s, %s/%s)",
+		i.AppName, i.Version, i.shortCommit(), i.BuildDate, i.GoVersion, runtime.GOOS, runtime.GOARCH)
+}
+
+// Short returns Version plus the first 7 characters of GitCommit (or the
+// full commit if it's shorter than that), e.g. "1.13.1-a1b2c3d".
+func (i Info) Short() string {
+	commit := i.shortCommit()
+	if commit == "" {
+		return i.Version
+	}
+	return i.Version + "-" + commit
+}
+
+// shortCommit returns the first 7 characters of GitCommit, or the full
+// commit if it's shorter than that.
+func (i Info) shortCommit() string {
+	commit := i.GitCommit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	return commit
+}
+
+// GetInfo returns TrustGate's build metadata. GitCommit/GitTreeState/
+// BuildDate come from ldflags when the binary was built with them; when
+// they're empty (e.g. `go run ./cmd/trustgate`), it falls back to whatever
+// runtime/debug.ReadBuildInfo() can recover from the module's VCS metadata.
+func GetInfo() Info {
+	commit, treeState, date := gitCommit, gitTreeState, buildDate
+
+	if commit == "" || date == "" {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range bi.Settings {
+				switch setting.Key {
+				case "vcs.revision":
+					if commit == "" {
+						commit = setting.Value
+					}
+				case "vcs.time":
+					if date == "" {
+						date = setting.Value
+					}
+				case "vcs.modified":
+					if treeState == "" {
+						if setting.Value == "true" {
+							treeState = "dirty"
+						} else {
+							treeState = "clean"
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return Info{
+		AppName:      AppName,
+		Version:      Version,
+		GitCommit:    commit,
+		GitTreeState: treeState,
+		BuildDate:    date,
+		GoVersion:    runtime.Version(),
+		Platform:     fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
+		Compiler:     runtime.Compiler,
+	}
+}
@@ -0,0 +1,42 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetInfo_FallsBackWhenLdflagsEmpty(t *testing.T) {
+	// gitCommit/gitTreeState/buildDate are empty in this test binary (no
+	// -ldflags), so GetInfo falls back to runtime/debug.ReadBuildInfo().
+	// Test binaries carry no VCS stamp, so GitCommit may still be empty;
+	// only the fields that don't depend on it are asserted.
+	info := GetInfo()
+
+	assert.Equal(t, AppName, info.AppName)
+	assert.Equal(t, Version, info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.NotEmpty(t, info.Platform)
+}
+
+func TestInfo_Short(t *testing.T) {
+	info := Info{Version: "1.13.1", GitCommit: "a1b2c3d4e5f6"}
+	assert.Equal(t, "1.13.1-a1b2c3d", info.Short())
+}
+
+func TestInfo_Short_NoCommit(t *testing.T) {
+	info := Info{Version: "1.13.1"}
+	assert.Equal(t, "1.13.1", info.Short())
+}
+
+func TestInfo_String_ContainsAppNameAndVersion(t *testing.T) {
+	info := GetInfo()
+	assert.Contains(t, info.String(), AppName)
+	assert.Contains(t, info.String(), Version)
+}
+
+func TestInfo_String_ContainsActualCommitNotShort(t *testing.T) {
+	info := Info{AppName: AppName, Version: "1.13.1", GitCommit: "a1b2c3d4e5f6"}
+	assert.Contains(t, info.String(), "commit a1b2c3d,")
+	assert.NotContains(t, info.String(), "commit 1.13.1-a1b2c3d,")
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultConditionHistoryLimit is how many ConditionTransition entries
+// RecordTransition keeps before trimming the oldest, unless overridden via
+// TrimHistory.
+const DefaultConditionHistoryLimit = 20
+
+// ConditionTransition records a single change applied to a HelmRelease's
+// conditions, so operators can audit why e.g. Ready flipped from
+// False(InstallFailed) to True(InstallSucceeded) without correlating
+// controller logs, mirroring the forensic trail Helm's own release history
+// provides at the condition level.
+type ConditionTransition struct {
+	Type               string                 `json:"type"`
+	FromStatus         metav1.ConditionStatus `json:"fromStatus,omitempty"`
+	FromReason         string                 `json:"fromReason,omitempty"`
+	ToStatus           metav1.ConditionStatus `json:"toStatus"`
+	ToReason           string                 `json:"toReason"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+	Time               metav1.Time            `json:"time"`
+}
+
+// RecordTransition appends a ConditionTransition describing old -> new to
+// obj's ConditionHistory and trims it to DefaultConditionHistoryLimit. old
+// may be nil when the condition type didn't previously exist.
+func RecordTransition(obj *HelmRelease, old, new *metav1.Condition) {
+	if new == nil {
+		return
+	}
+
+	transition := ConditionTransition{
+		Type:               new.Type,
+		ToStatus:           new.Status,
+		ToReason:           new.Reason,
+		ObservedGeneration: new.ObservedGeneration,
+		Time:               new.LastTransitionTime,
+	}
+	if old != nil {
+		transition.FromStatus = old.Status
+		transition.FromReason = old.Reason
+	}
+
+	obj.Status.ConditionHistory = append(obj.Status.ConditionHistory, transition)
+	TrimHistory(obj, DefaultConditionHistoryLimit)
+}
+
+// TrimHistory caps obj's ConditionHistory to the most recent max entries.
+func TrimHistory(obj *HelmRelease, max int) {
+	if len(obj.Status.ConditionHistory) <= max {
+		return
+	}
+	obj.Status.ConditionHistory = obj.Status.ConditionHistory[len(obj.Status.ConditionHistory)-max:]
+}
+
+// LatestTransition returns the most recently recorded transition, or nil
+// when ConditionHistory is empty. Used to populate the
+// `kubectl get helmrelease` printer column showing the most recent
+// transition reason.
+func (in *HelmRelease) LatestTransition() *ConditionTransition {
+	history := in.Status.ConditionHistory
+	if len(history) == 0 {
+		return nil
+	}
+	return &history[len(history)-1]
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRecordTransition_AppendsAndCapturesOldState(t *testing.T) {
+	obj := &HelmRelease{}
+	old := &metav1.Condition{Type: ReleasedCondition, Status: metav1.ConditionFalse, Reason: InstallFailedReason}
+	new := &metav1.Condition{Type: ReleasedCondition, Status: metav1.ConditionTrue, Reason: InstallSucceededReason}
+
+	RecordTransition(obj, old, new)
+
+	if len(obj.Status.ConditionHistory) != 1 {
+		t.Fatalf("expected 1 transition, got %d", len(obj.Status.ConditionHistory))
+	}
+	got := obj.Status.ConditionHistory[0]
+	if got.FromReason != InstallFailedReason || got.ToReason != InstallSucceededReason {
+		t.Errorf("unexpected transition: %+v", got)
+	}
+}
+
+func TestTrimHistory_CapsToMax(t *testing.T) {
+	obj := &HelmRelease{}
+	for i := 0; i < 25; i++ {
+		RecordTransition(obj, nil, &metav1.Condition{Type: ReleasedCondition, Status: metav1.ConditionTrue, Reason: "r"})
+	}
+
+	if len(obj.Status.ConditionHistory) != DefaultConditionHistoryLimit {
+		t.Errorf("expected history capped to %d, got %d", DefaultConditionHistoryLimit, len(obj.Status.ConditionHistory))
+	}
+}
+
+func TestLatestTransition_ReturnsNilWhenEmpty(t *testing.T) {
+	obj := &HelmRelease{}
+	if obj.LatestTransition() != nil {
+		t.Error("expected nil for empty ConditionHistory")
+	}
+}
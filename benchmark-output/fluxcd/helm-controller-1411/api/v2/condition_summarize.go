@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// progressingReasons maps each in-progress release action to the
+// Progressing reason that should be reported while it is underway.
+var progressingReasons = map[string]string{
+	"install":   InstallInProgressReason,
+	"upgrade":   UpgradeInProgressReason,
+	"rollback":  RollbackInProgressReason,
+	"uninstall": UninstallInProgressReason,
+}
+
+// markProgressing sets Progressing=True with the reason for the named
+// action ("install", "upgrade", "rollback", or "uninstall"), then
+// re-summarizes so Ready reflects that work is in flight even while the
+// terminal Released/Remediated condition hasn't settled yet.
+func markProgressing(obj *HelmRelease, action, messageFmt string, messageArgs ...interface{}) {
+	reason, ok := progressingReasons[action]
+	if !ok {
+		reason = ReconcileCompleteReason
+	}
+	conditions.MarkTrue(obj, ProgressingCondition, reason, messageFmt, messageArgs...)
+	summarize(obj)
+}
+
+// clearProgressing marks Progressing=False with ReconcileCompleteReason once
+// the in-flight release operation has settled (whether it succeeded or
+// failed), then re-summarizes Ready.
+func clearProgressing(obj *HelmRelease) {
+	conditions.MarkFalse(obj, ProgressingCondition, ReconcileCompleteReason, "reconciliation complete")
+	summarize(obj)
+}
+
+// summarize recomputes the Ready condition from the HelmRelease's other
+// conditions. Ready incorporates Progressing so that consumers watching
+// only Ready still observe intermediate work in flight, not just the
+// terminal Released/Remediated outcome.
+func summarize(obj *HelmRelease) {
+	before := conditions.Get(obj, meta.ReadyCondition)
+
+	conditions.SetSummary(obj,
+		meta.ReadyCondition,
+		conditions.WithConditions(
+			ProgressingCondition,
+			ReleasedCondition,
+			RemediatedCondition,
+			TestSuccessCondition,
+		),
+		conditions.WithNegativePolarityConditions(
+			ProgressingCondition,
+		),
+	)
+
+	after := conditions.Get(obj, meta.ReadyCondition)
+	if after != nil && (before == nil || before.Status != after.Status || before.Reason != after.Reason) {
+		RecordTransition(obj, before, after)
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+func TestMarkProgressing_SetsReasonForAction(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace", Generation: 1},
+	}
+
+	markProgressing(obj, "install", "installing release")
+
+	if !conditions.IsTrue(obj, ProgressingCondition) {
+		t.Errorf("expected ProgressingCondition to be True, got: %v", conditions.Get(obj, ProgressingCondition))
+	}
+	if conditions.GetReason(obj, ProgressingCondition) != InstallInProgressReason {
+		t.Errorf("expected reason %s, got %s", InstallInProgressReason, conditions.GetReason(obj, ProgressingCondition))
+	}
+}
+
+func TestClearProgressing_SetsReconcileCompleteReason(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace", Generation: 1},
+	}
+
+	markProgressing(obj, "upgrade", "upgrading release")
+	clearProgressing(obj)
+
+	if !conditions.IsFalse(obj, ProgressingCondition) {
+		t.Errorf("expected ProgressingCondition to be False after clearing, got: %v", conditions.Get(obj, ProgressingCondition))
+	}
+	if conditions.GetReason(obj, ProgressingCondition) != ReconcileCompleteReason {
+		t.Errorf("expected reason %s, got %s", ReconcileCompleteReason, conditions.GetReason(obj, ProgressingCondition))
+	}
+}
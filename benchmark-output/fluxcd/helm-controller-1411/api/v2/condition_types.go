@@ -0,0 +1,115 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+const (
+	// ReleasedCondition represents the status of the last release attempt
+	// (install/upgrade/test) against the latest desired state.
+	ReleasedCondition string = "Released"
+
+	// TestSuccessCondition represents the status of the last test run
+	// against the latest desired state.
+	TestSuccessCondition string = "TestSuccess"
+
+	// RemediatedCondition represents the status of the last remediation
+	// attempt (uninstall/rollback) due to a failure of the last
+	// release attempt against the latest desired state.
+	RemediatedCondition string = "Remediated"
+)
+
+// ProgressingCondition indicates that an install, upgrade, rollback, or
+// uninstall is currently underway for the HelmRelease, mirroring the
+// "Progressing" rollup condition used by operator-controller's
+// ClusterExtension. Unlike Released/Remediated, Progressing is transient: it
+// is always cleared (set to False with ReconcileCompleteReason) once the
+// release operation settles, whatever its outcome.
+const ProgressingCondition string = "Progressing"
+
+const (
+	// InstallSucceededReason represents the fact that the Helm install for a
+	// HelmRelease succeeded.
+	InstallSucceededReason string = "InstallSucceeded"
+
+	// InstallFailedReason represents the fact that the Helm install for a
+	// HelmRelease failed.
+	InstallFailedReason string = "InstallFailed"
+
+	// UpgradeSucceededReason represents the fact that the Helm upgrade for a
+	// HelmRelease succeeded.
+	UpgradeSucceededReason string = "UpgradeSucceeded"
+
+	// UpgradeFailedReason represents the fact that the Helm upgrade for a
+	// HelmRelease failed.
+	UpgradeFailedReason string = "UpgradeFailed"
+
+	// TestSucceededReason represents the fact that the Helm tests for a
+	// HelmRelease succeeded.
+	TestSucceededReason string = "TestSucceeded"
+
+	// TestFailedReason represents the fact that the Helm tests for a
+	// HelmRelease failed.
+	TestFailedReason string = "TestFailed"
+
+	// RollbackSucceededReason represents the fact that the Helm rollback for
+	// a HelmRelease succeeded.
+	RollbackSucceededReason string = "RollbackSucceeded"
+
+	// RollbackFailedReason represents the fact that the Helm rollback for a
+	// HelmRelease failed.
+	RollbackFailedReason string = "RollbackFailed"
+
+	// UninstallSucceededReason represents the fact that the Helm uninstall
+	// for a HelmRelease succeeded.
+	UninstallSucceededReason string = "UninstallSucceeded"
+
+	// UninstallFailedReason represents the fact that the Helm uninstall for
+	// a HelmRelease failed.
+	UninstallFailedReason string = "UninstallFailed"
+
+	// ArtifactFailedReason represents the fact that the source artifact for
+	// a HelmRelease could not be retrieved or processed.
+	ArtifactFailedReason string = "ArtifactFailed"
+
+	// ForceReplacedReason represents the fact that a HelmRelease stuck in
+	// InstallFailedReason or UpgradeFailedReason beyond its configured
+	// FailureThreshold was uninstalled and re-installed from scratch,
+	// rather than remediated with another install/upgrade attempt.
+	ForceReplacedReason string = "ForceReplaced"
+)
+
+const (
+	// InstallInProgressReason signals that an install is currently being
+	// performed for the HelmRelease.
+	InstallInProgressReason string = "InstallInProgress"
+
+	// UpgradeInProgressReason signals that an upgrade is currently being
+	// performed for the HelmRelease.
+	UpgradeInProgressReason string = "UpgradeInProgress"
+
+	// RollbackInProgressReason signals that a rollback is currently being
+	// performed for the HelmRelease.
+	RollbackInProgressReason string = "RollbackInProgress"
+
+	// UninstallInProgressReason signals that an uninstall is currently being
+	// performed for the HelmRelease.
+	UninstallInProgressReason string = "UninstallInProgress"
+
+	// ReconcileCompleteReason signals that the in-flight release operation
+	// has settled; Progressing is cleared with this reason regardless of
+	// whether the operation ultimately succeeded or failed.
+	ReconcileCompleteReason string = "ReconcileComplete"
+)
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// replaceCondition atomically swaps condition type `from` for `to`: `to` is
+// marked with the given status/reason/message, but carries over `from`'s
+// LastTransitionTime rather than resetting it to now, since the underlying
+// release state didn't just transition, the stale condition is only now
+// being relabeled to match it. It is used to auto-heal stale conditions
+// left behind by a previous reconciliation outcome, e.g. replacing a stale
+// RemediatedCondition=True with ReleasedCondition=True once the current
+// release matches the desired state again.
+func replaceCondition(obj conditions.Setter, from, to, reason, message string, status metav1.ConditionStatus) {
+	old := conditions.Get(obj, from)
+	conditions.Delete(obj, from)
+	if status == metav1.ConditionTrue {
+		conditions.MarkTrue(obj, to, reason, "%s", message)
+	} else {
+		conditions.MarkFalse(obj, to, reason, "%s", message)
+	}
+
+	if old == nil {
+		return
+	}
+	cs := obj.GetConditions()
+	for i := range cs {
+		if cs[i].Type == to {
+			cs[i].LastTransitionTime = old.LastTransitionTime
+			break
+		}
+	}
+	obj.SetConditions(cs)
+}
+
+// healStaleRemediation heals the conditions of a HelmRelease whose current
+// release already matches the desired chart+values, where
+// RemediatedCondition is still True from a prior rollback that happens to
+// land on the now-desired spec. It does not compare the release with the
+// desired chart+values itself; it only returns early when History is
+// empty. Otherwise it swaps a True Remediated for
+// Released=True/UpgradeSucceededReason, deletes any TestSuccessCondition
+// present, then re-summarizes so Ready reflects the healed state.
+func healStaleRemediation(obj *HelmRelease) {
+	latest := obj.Status.History.Latest()
+	if latest == nil {
+		return
+	}
+
+	if conditions.IsTrue(obj, RemediatedCondition) {
+		message := obj.MessageFormatter().Render(
+			UpgradeSucceededReason,
+			obj.FullReleaseName(), obj.VersionedChartName(),
+		)
+		replaceCondition(obj, RemediatedCondition, ReleasedCondition,
+			UpgradeSucceededReason, message, metav1.ConditionTrue,
+		)
+	}
+
+	if conditions.Has(obj, TestSuccessCondition) {
+		conditions.Delete(obj, TestSuccessCondition)
+	}
+
+	summarize(obj)
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+func TestHealStaleRemediation_SwapsRemediatedForReleased(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Status: HelmReleaseStatus{
+			History: Snapshots{{Version: 2, Name: "test-release", Namespace: "test-namespace"}},
+			Conditions: []metav1.Condition{
+				*conditions.TrueCondition(RemediatedCondition, "RollbackSucceeded", "rolled back"),
+				*conditions.TrueCondition(TestSuccessCondition, "TestSucceeded", "tests passed on old revision"),
+			},
+		},
+	}
+
+	healStaleRemediation(obj)
+
+	if conditions.Has(obj, RemediatedCondition) {
+		t.Error("expected RemediatedCondition to be removed")
+	}
+	if conditions.Has(obj, TestSuccessCondition) {
+		t.Error("expected stale TestSuccessCondition to be removed")
+	}
+	if !conditions.IsTrue(obj, ReleasedCondition) {
+		t.Errorf("expected ReleasedCondition to be True, got: %v", conditions.Get(obj, ReleasedCondition))
+	}
+	if conditions.GetReason(obj, ReleasedCondition) != UpgradeSucceededReason {
+		t.Errorf("expected reason %s, got %s", UpgradeSucceededReason, conditions.GetReason(obj, ReleasedCondition))
+	}
+}
+
+func TestHealStaleRemediation_UsesCustomMessageFormat(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Spec: HelmReleaseSpec{
+			EventMessageFormats: &MessageFormats{UpgradeSuccess: "release %s is live (chart %s)"},
+		},
+		Status: HelmReleaseStatus{
+			History: Snapshots{{Version: 2, Name: "test-release", Namespace: "test-namespace"}},
+			Conditions: []metav1.Condition{
+				*conditions.TrueCondition(RemediatedCondition, "RollbackSucceeded", "rolled back"),
+			},
+		},
+	}
+
+	healStaleRemediation(obj)
+
+	got := conditions.Get(obj, ReleasedCondition).Message
+	want := "release " + obj.FullReleaseName() + " is live (chart " + obj.VersionedChartName() + ")"
+	if got != want {
+		t.Errorf("expected custom template to round-trip into ReleasedCondition message, got: %q, want: %q", got, want)
+	}
+}
+
+func TestHealStaleRemediation_PreservesLastTransitionTime(t *testing.T) {
+	remediatedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	remediated := conditions.TrueCondition(RemediatedCondition, "RollbackSucceeded", "rolled back")
+	remediated.LastTransitionTime = remediatedAt
+
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Status: HelmReleaseStatus{
+			History:    Snapshots{{Version: 2, Name: "test-release", Namespace: "test-namespace"}},
+			Conditions: []metav1.Condition{*remediated},
+		},
+	}
+
+	healStaleRemediation(obj)
+
+	got := conditions.Get(obj, ReleasedCondition)
+	if got == nil {
+		t.Fatal("expected ReleasedCondition to be set")
+	}
+	if !got.LastTransitionTime.Equal(&remediatedAt) {
+		t.Errorf("expected ReleasedCondition to carry over RemediatedCondition's LastTransitionTime %v, got %v", remediatedAt, got.LastTransitionTime)
+	}
+}
+
+func TestHealStaleRemediation_NoOpWithoutHistory(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Status: HelmReleaseStatus{
+			History: Snapshots{},
+			Conditions: []metav1.Condition{
+				*conditions.TrueCondition(RemediatedCondition, "RollbackSucceeded", "rolled back"),
+			},
+		},
+	}
+
+	healStaleRemediation(obj)
+
+	if !conditions.IsTrue(obj, RemediatedCondition) {
+		t.Error("expected RemediatedCondition to be left untouched when there is no release history")
+	}
+}
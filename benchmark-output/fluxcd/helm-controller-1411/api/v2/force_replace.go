@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// defaultFailureThreshold is the number of consecutive failed
+// install/upgrade attempts, at the current spec, tolerated before Force (if
+// enabled) triggers a replace instead of another attempt.
+const defaultFailureThreshold = 1
+
+// ShouldForceReplace reports whether a HelmRelease stuck in
+// InstallFailedReason or UpgradeFailedReason should be replaced (uninstalled
+// then installed from scratch) rather than retried, based on the Force and
+// FailureThreshold configured for the action that's currently failing.
+// attempts is the number of consecutive failures observed for that action
+// at the current spec/chart.
+func ShouldForceReplace(obj *HelmRelease, attempts int) bool {
+	switch conditions.GetReason(obj, ReleasedCondition) {
+	case InstallFailedReason:
+		install := obj.Spec.Install
+		return install != nil && install.Force && attempts > failureThreshold(install.FailureThreshold)
+	case UpgradeFailedReason:
+		upgrade := obj.Spec.Upgrade
+		return upgrade != nil && upgrade.Force && attempts > failureThreshold(upgrade.FailureThreshold)
+	default:
+		return false
+	}
+}
+
+func failureThreshold(t *int) int {
+	if t == nil {
+		return defaultFailureThreshold
+	}
+	return *t
+}
+
+// ForceReplace records the outcome of a successful force-replace
+// (uninstall-then-install) cycle: it prepends next to History, pointing
+// next.Replaces at the revision it replaced, marks ReleasedCondition=True
+// with ForceReplacedReason, and re-summarizes so meta.ReadyCondition flips
+// to match, mirroring the invariant the stale-condition healing path
+// relies on.
+func ForceReplace(obj *HelmRelease, next Snapshot) {
+	if previous := obj.Status.History.Latest(); previous != nil {
+		next.Replaces = previous.Version
+	}
+	obj.Status.History = append(Snapshots{next}, obj.Status.History...)
+
+	message := obj.MessageFormatter().Render(
+		ForceReplacedReason,
+		obj.FullReleaseName(), obj.VersionedChartName(),
+	)
+	conditions.MarkTrue(obj, ReleasedCondition, ForceReplacedReason, "%s", message)
+	summarize(obj)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+func TestShouldForceReplace_InstallFailedBeyondThreshold(t *testing.T) {
+	threshold := 2
+	obj := &HelmRelease{
+		Spec: HelmReleaseSpec{
+			Install: &Install{Force: true, FailureThreshold: &threshold},
+		},
+		Status: HelmReleaseStatus{
+			Conditions: []metav1.Condition{
+				*conditions.FalseCondition(ReleasedCondition, InstallFailedReason, "install failed"),
+			},
+		},
+	}
+
+	if ShouldForceReplace(obj, 2) {
+		t.Error("expected no replace at the threshold, only beyond it")
+	}
+	if !ShouldForceReplace(obj, 3) {
+		t.Error("expected replace once attempts exceed FailureThreshold")
+	}
+}
+
+func TestShouldForceReplace_FalseWithoutForce(t *testing.T) {
+	obj := &HelmRelease{
+		Spec: HelmReleaseSpec{Install: &Install{Force: false}},
+		Status: HelmReleaseStatus{
+			Conditions: []metav1.Condition{
+				*conditions.FalseCondition(ReleasedCondition, InstallFailedReason, "install failed"),
+			},
+		},
+	}
+
+	if ShouldForceReplace(obj, 10) {
+		t.Error("expected no replace when Force is disabled")
+	}
+}
+
+func TestShouldForceReplace_FalseWhenNotFailed(t *testing.T) {
+	obj := &HelmRelease{
+		Spec: HelmReleaseSpec{Install: &Install{Force: true}},
+		Status: HelmReleaseStatus{
+			Conditions: []metav1.Condition{
+				*conditions.TrueCondition(ReleasedCondition, InstallSucceededReason, "install succeeded"),
+			},
+		},
+	}
+
+	if ShouldForceReplace(obj, 10) {
+		t.Error("expected no replace when the release is not stuck failed")
+	}
+}
+
+func TestForceReplace_MarksReleasedAndReady(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Status: HelmReleaseStatus{
+			History: Snapshots{{Version: 3, Name: "test-release", Namespace: "test-namespace", ChartName: "podinfo", ChartVersion: "6.0.0"}},
+			Conditions: []metav1.Condition{
+				*conditions.FalseCondition(ReleasedCondition, InstallFailedReason, "install failed"),
+			},
+		},
+	}
+
+	ForceReplace(obj, Snapshot{Version: 4, Name: "test-release", Namespace: "test-namespace", ChartName: "podinfo", ChartVersion: "6.0.0"})
+
+	if !conditions.IsTrue(obj, ReleasedCondition) {
+		t.Errorf("expected ReleasedCondition to be True, got: %v", conditions.Get(obj, ReleasedCondition))
+	}
+	if conditions.GetReason(obj, ReleasedCondition) != ForceReplacedReason {
+		t.Errorf("expected reason %s, got %s", ForceReplacedReason, conditions.GetReason(obj, ReleasedCondition))
+	}
+	if !conditions.IsTrue(obj, meta.ReadyCondition) {
+		t.Errorf("expected %s to be True after summarize, got: %v", meta.ReadyCondition, conditions.Get(obj, meta.ReadyCondition))
+	}
+
+	latest := obj.Status.History.Latest()
+	if latest == nil || latest.Version != 4 {
+		t.Fatalf("expected latest snapshot to be the new version 4, got: %+v", latest)
+	}
+	if latest.Replaces != 3 {
+		t.Errorf("expected new snapshot to record Replaces=3, got %d", latest.Replaces)
+	}
+}
+
+func TestForceReplace_UsesCustomMessageFormat(t *testing.T) {
+	obj := &HelmRelease{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-release", Namespace: "test-namespace"},
+		Spec: HelmReleaseSpec{
+			EventMessageFormats: &MessageFormats{ForceReplaced: "replaced %s (chart %s)"},
+		},
+		Status: HelmReleaseStatus{
+			History: Snapshots{{Version: 3, Name: "test-release", Namespace: "test-namespace", ChartName: "podinfo", ChartVersion: "6.0.0"}},
+			Conditions: []metav1.Condition{
+				*conditions.FalseCondition(ReleasedCondition, InstallFailedReason, "install failed"),
+			},
+		},
+	}
+
+	ForceReplace(obj, Snapshot{Version: 4, Name: "test-release", Namespace: "test-namespace", ChartName: "podinfo", ChartVersion: "6.0.0"})
+
+	got := conditions.Get(obj, ReleasedCondition).Message
+	want := "replaced " + obj.FullReleaseName() + " (chart " + obj.VersionedChartName() + ")"
+	if got != want {
+		t.Errorf("expected custom template to round-trip into ReleasedCondition message, got: %q, want: %q", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HelmReleaseSpec defines the desired state of a Helm release.
+type HelmReleaseSpec struct {
+	// ReleaseName used for the Helm release. Defaults to a composition of
+	// '[TargetNamespace-]Name'.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// TargetNamespace to target when performing operations for the
+	// HelmRelease. Defaults to the namespace of the HelmRelease.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// StorageNamespace used for the Helm storage.
+	// Defaults to the namespace of the HelmRelease.
+	// +optional
+	StorageNamespace string `json:"storageNamespace,omitempty"`
+
+	// EventMessageFormats overrides the default human-readable messages
+	// used when populating Released/Remediated/TestSuccess conditions,
+	// without requiring a controller rebuild (e.g. to keep a
+	// notification-controller inclusionList regex matching). Unspecified
+	// fields keep the built-in default template.
+	// +optional
+	EventMessageFormats *MessageFormats `json:"eventMessageFormats,omitempty"`
+
+	// Install holds the configuration for Helm install actions for this
+	// HelmRelease.
+	// +optional
+	Install *Install `json:"install,omitempty"`
+
+	// Upgrade holds the configuration for Helm upgrade actions for this
+	// HelmRelease.
+	// +optional
+	Upgrade *Upgrade `json:"upgrade,omitempty"`
+}
+
+// Install holds the configuration for Helm install actions performed for a
+// HelmRelease.
+type Install struct {
+	// Force, if a release is stuck in InstallFailedReason beyond
+	// FailureThreshold, makes the reconciler uninstall and re-install the
+	// release from scratch instead of retrying the install, analogous to
+	// `helm upgrade --install --force` replacing a FAILED deployment.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed install
+	// attempts, at the current spec, that are tolerated; once failures
+	// exceed it, Force (if enabled) triggers a replace instead of another
+	// install attempt. Defaults to 1 when unset.
+	// +optional
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+}
+
+// Upgrade holds the configuration for Helm upgrade actions performed for a
+// HelmRelease.
+type Upgrade struct {
+	// Force, if a release is stuck in UpgradeFailedReason beyond
+	// FailureThreshold, makes the reconciler uninstall and re-install the
+	// release from scratch instead of retrying the upgrade, analogous to
+	// `helm upgrade --install --force` replacing a FAILED deployment.
+	// +optional
+	Force bool `json:"force,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed upgrade
+	// attempts, at the current spec, that are tolerated; once failures
+	// exceed it, Force (if enabled) triggers a replace instead of another
+	// upgrade attempt. Defaults to 1 when unset.
+	// +optional
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+}
+
+// HelmReleaseStatus defines the observed state of a Helm release.
+type HelmReleaseStatus struct {
+	// ObservedGeneration is the last observed generation of the
+	// HelmRelease object.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions holds the conditions for the HelmRelease.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// History holds the history of Helm releases performed for this
+	// HelmRelease up to the last successfully completed release.
+	// +optional
+	History Snapshots `json:"history,omitempty"`
+
+	// ConditionHistory is a capped ring buffer of transitions applied to
+	// Conditions via RecordTransition, giving operators a forensic trail of
+	// why e.g. Ready flipped from False(InstallFailed) to
+	// True(InstallSucceeded).
+	// +optional
+	ConditionHistory []ConditionTransition `json:"conditionHistory,omitempty"`
+}
+
+// Snapshot captures a point-in-time state of a Helm release, as recorded in
+// Helm's own release storage.
+type Snapshot struct {
+	// Version is the version of the Helm release.
+	Version int `json:"version"`
+
+	// Name is the name of the Helm release.
+	Name string `json:"name"`
+
+	// Namespace is the namespace the Helm release is made to.
+	Namespace string `json:"namespace"`
+
+	// Status is the current state of the Helm release.
+	// +optional
+	Status string `json:"status,omitempty"`
+
+	// ChartName is the name of the chart used for this release.
+	// +optional
+	ChartName string `json:"chartName,omitempty"`
+
+	// ChartVersion is the version of the chart used for this release.
+	// +optional
+	ChartVersion string `json:"chartVersion,omitempty"`
+
+	// Replaces is the Version of the release this snapshot replaced via a
+	// force-replace (uninstall-then-install) cycle. Zero if this snapshot
+	// was not the result of one.
+	// +optional
+	Replaces int `json:"replaces,omitempty"`
+}
+
+// VersionedChartName returns "<ChartName>@<ChartVersion>", or "" if
+// ChartName is unset.
+func (in Snapshot) VersionedChartName() string {
+	if in.ChartName == "" {
+		return ""
+	}
+	return in.ChartName + "@" + in.ChartVersion
+}
+
+// Snapshots is a list of Snapshot, ordered from most recent to oldest.
+type Snapshots []Snapshot
+
+// Latest returns the most recent snapshot, or nil when there is none.
+func (in Snapshots) Latest() *Snapshot {
+	if len(in) == 0 {
+		return nil
+	}
+	return &in[0]
+}
+
+// +kubebuilder:printcolumn:name="Last Transition",type="string",JSONPath=".status.conditionHistory[-1:].toReason"
+
+// HelmRelease is the Schema for the helmreleases API.
+type HelmRelease struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HelmReleaseSpec   `json:"spec,omitempty"`
+	Status HelmReleaseStatus `json:"status,omitempty"`
+}
+
+// GetConditions returns the status conditions of the object.
+func (in *HelmRelease) GetConditions() []metav1.Condition {
+	return in.Status.Conditions
+}
+
+// SetConditions sets the status conditions on the object.
+func (in *HelmRelease) SetConditions(conditions []metav1.Condition) {
+	in.Status.Conditions = conditions
+}
+
+// FullReleaseName returns the fully qualified name of the Helm release,
+// i.e. '<TargetNamespace>/<ReleaseName>'.
+func (in *HelmRelease) FullReleaseName() string {
+	namespace := in.Spec.TargetNamespace
+	if namespace == "" {
+		namespace = in.Namespace
+	}
+	releaseName := in.Spec.ReleaseName
+	if releaseName == "" {
+		releaseName = in.Name
+	}
+	return namespace + "/" + releaseName
+}
+
+// VersionedChartName returns the "<chart>@<version>" of the latest release
+// in history, or "" when there is no history yet.
+func (in *HelmRelease) VersionedChartName() string {
+	latest := in.Status.History.Latest()
+	if latest == nil {
+		return ""
+	}
+	return latest.VersionedChartName()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MessageFormats is a typed view over the reason->template map
+// MessageFormatter wraps, giving HelmReleaseSpec.EventMessageFormats a
+// discoverable, documented shape instead of a free-form map, while still
+// rendering through the same MessageFormatter machinery (and the same
+// backward-compatible defaults) as RegisterMessageTemplate.
+type MessageFormats struct {
+	InstallSuccess string `json:"installSuccess,omitempty"`
+	InstallFailure string `json:"installFailure,omitempty"`
+	UpgradeSuccess string `json:"upgradeSuccess,omitempty"`
+	UpgradeFailure string `json:"upgradeFailure,omitempty"`
+	TestSuccess    string `json:"testSuccess,omitempty"`
+	Remediated     string `json:"remediated,omitempty"`
+	ForceReplaced  string `json:"forceReplaced,omitempty"`
+}
+
+// requiredVerbs is how many '%s' verbs each reason's template must contain,
+// matching the positional args the controller renders it with.
+var requiredVerbs = map[string]int{
+	InstallSucceededReason:  2,
+	InstallFailedReason:     5,
+	UpgradeSucceededReason:  2,
+	UpgradeFailedReason:     5,
+	TestSucceededReason:     1,
+	RollbackSucceededReason: 2,
+	ForceReplacedReason:     2,
+}
+
+// toMap converts non-empty fields to a reason->template map keyed the same
+// way MessageFormatter.RegisterMessageTemplate expects.
+func (f MessageFormats) toMap() map[string]string {
+	m := map[string]string{}
+	if f.InstallSuccess != "" {
+		m[InstallSucceededReason] = f.InstallSuccess
+	}
+	if f.InstallFailure != "" {
+		m[InstallFailedReason] = f.InstallFailure
+	}
+	if f.UpgradeSuccess != "" {
+		m[UpgradeSucceededReason] = f.UpgradeSuccess
+	}
+	if f.UpgradeFailure != "" {
+		m[UpgradeFailedReason] = f.UpgradeFailure
+	}
+	if f.TestSuccess != "" {
+		m[TestSucceededReason] = f.TestSuccess
+	}
+	if f.Remediated != "" {
+		m[RollbackSucceededReason] = f.Remediated
+	}
+	if f.ForceReplaced != "" {
+		m[ForceReplacedReason] = f.ForceReplaced
+	}
+	return m
+}
+
+// ValidateMessageFormats checks that every template set on f contains the
+// number of '%s' verbs the controller will render it with, returning an
+// error naming the reason of an offending template instead of producing a
+// silently-mangled condition message at runtime. Templates are checked in
+// map order, so when several are wrong, which one is reported is not
+// defined.
+func ValidateMessageFormats(f MessageFormats) error {
+	for reason, tmpl := range f.toMap() {
+		want := requiredVerbs[reason]
+		if got := strings.Count(tmpl, "%s"); got != want {
+			return fmt.Errorf("messageFormats: template for %s has %d %%s verbs, want %d", reason, got, want)
+		}
+	}
+	return nil
+}
+
+// BuildMessageFormatter returns a MessageFormatter seeded with the built-in
+// defaults and overridden by f's non-empty fields.
+func BuildMessageFormatter(f MessageFormats) *MessageFormatter {
+	return NewMessageFormatter().ApplyOverrides(f.toMap())
+}
+
+// MessageFormatter returns the MessageFormatter that condition/event
+// messages for obj should be rendered through, seeded with the built-in
+// defaults and overridden by obj.Spec.EventMessageFormats when set.
+func (in *HelmRelease) MessageFormatter() *MessageFormatter {
+	if in.Spec.EventMessageFormats == nil {
+		return NewMessageFormatter()
+	}
+	return BuildMessageFormatter(*in.Spec.EventMessageFormats)
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import "testing"
+
+func TestValidateMessageFormats_RejectsWrongVerbCount(t *testing.T) {
+	err := ValidateMessageFormats(MessageFormats{InstallSuccess: "no verbs here"})
+	if err == nil {
+		t.Fatal("expected error for missing verbs")
+	}
+}
+
+func TestValidateMessageFormats_AcceptsMatchingVerbCount(t *testing.T) {
+	err := ValidateMessageFormats(MessageFormats{InstallSuccess: "release %s with chart %s"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestBuildMessageFormatter_OverridesRemediated(t *testing.T) {
+	f := BuildMessageFormatter(MessageFormats{Remediated: "remediated %s / %s"})
+
+	if got := f.Render(RollbackSucceededReason, "a", "b"); got != "remediated a / b" {
+		t.Errorf("Remediated override not applied, got: %s", got)
+	}
+}
+
+func TestBuildMessageFormatter_OverridesOnlySpecifiedFields(t *testing.T) {
+	f := BuildMessageFormatter(MessageFormats{UpgradeSuccess: "custom %s / %s"})
+
+	if got := f.Render(UpgradeSucceededReason, "a", "b"); got != "custom a / b" {
+		t.Errorf("UpgradeSuccess override not applied, got: %s", got)
+	}
+	if got := f.Render(InstallSucceededReason, "a", "b"); got == "" {
+		t.Error("expected InstallSucceeded to still render via the default template")
+	}
+}
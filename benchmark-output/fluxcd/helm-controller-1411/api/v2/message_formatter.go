@@ -0,0 +1,83 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import "fmt"
+
+// defaultMessageTemplates holds the historical, hardcoded message strings
+// so that existing notification-controller inclusion/exclusion lists (e.g.
+// regexes like ".*.upgrade.*succeeded.*") keep matching after this package
+// gains the ability to localize or restructure messages.
+var defaultMessageTemplates = map[string]string{
+	InstallSucceededReason:  "Helm install succeeded for release %s with chart %s",
+	InstallFailedReason:     "Helm install failed for release %s/%s with chart %s@%s: %s",
+	UpgradeSucceededReason:  "Helm upgrade succeeded for release %s with chart %s",
+	UpgradeFailedReason:     "Helm upgrade failed for release %s/%s with chart %s@%s: %s",
+	ArtifactFailedReason:    "Could not retrieve artifact for release %s: %s",
+	RollbackSucceededReason: "Helm rollback succeeded for release %s with chart %s",
+	ForceReplacedReason:     "Helm release %s was force-replaced with chart %s",
+}
+
+// MessageFormatter renders the human-readable messages used when populating
+// HelmRelease conditions, so operators can override the wording per reason
+// (e.g. to localize it, or adapt it to their own log tooling) without
+// forking the controller.
+type MessageFormatter struct {
+	templates map[string]string
+}
+
+// NewMessageFormatter returns a MessageFormatter seeded with the built-in
+// default templates.
+func NewMessageFormatter() *MessageFormatter {
+	templates := make(map[string]string, len(defaultMessageTemplates))
+	for reason, tmpl := range defaultMessageTemplates {
+		templates[reason] = tmpl
+	}
+	return &MessageFormatter{templates: templates}
+}
+
+// RegisterMessageTemplate overrides the template used for reason. The
+// template must contain the same number of %s verbs as the default it
+// replaces, since Render is called positionally.
+func (f *MessageFormatter) RegisterMessageTemplate(reason, template string) {
+	f.templates[reason] = template
+}
+
+// Render formats the message for reason with args, falling back to
+// "%s"-joining args if reason has no registered template.
+func (f *MessageFormatter) Render(reason string, args ...interface{}) string {
+	tmpl, ok := f.templates[reason]
+	if !ok {
+		return fmt.Sprint(args...)
+	}
+	return fmt.Sprintf(tmpl, args...)
+}
+
+// ApplyOverrides layers a HelmReleaseSpec's per-release message template
+// overrides (HelmReleaseSpec.EventMessageFormats) on top of the defaults,
+// returning a new formatter so the package-level defaults are never
+// mutated.
+func (f *MessageFormatter) ApplyOverrides(overrides map[string]string) *MessageFormatter {
+	merged := NewMessageFormatter()
+	for reason, tmpl := range f.templates {
+		merged.templates[reason] = tmpl
+	}
+	for reason, tmpl := range overrides {
+		merged.templates[reason] = tmpl
+	}
+	return merged
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMessageFormatter_DefaultMatchesHistoricalSubstrings(t *testing.T) {
+	f := NewMessageFormatter()
+
+	got := f.Render(InstallSucceededReason, "my-release", "my-chart")
+	if !strings.Contains(got, "Helm install succeeded for release my-release with chart my-chart") {
+		t.Errorf("unexpected default message: %s", got)
+	}
+}
+
+func TestMessageFormatter_CustomTemplateRoundTrips(t *testing.T) {
+	f := NewMessageFormatter()
+	f.RegisterMessageTemplate(InstallSucceededReason, "release %s is live (chart %s)")
+
+	got := f.Render(InstallSucceededReason, "my-release", "my-chart")
+	if got != "release my-release is live (chart my-chart)" {
+		t.Errorf("custom template did not round-trip, got: %s", got)
+	}
+}
+
+func TestMessageFormatter_ApplyOverridesDoesNotMutateDefaults(t *testing.T) {
+	base := NewMessageFormatter()
+	overridden := base.ApplyOverrides(map[string]string{
+		InstallSucceededReason: "custom: %s / %s",
+	})
+
+	if base.Render(InstallSucceededReason, "a", "b") == overridden.Render(InstallSucceededReason, "a", "b") {
+		t.Error("expected overridden formatter to diverge from base")
+	}
+	if !strings.HasPrefix(base.Render(InstallSucceededReason, "a", "b"), "Helm install succeeded") {
+		t.Error("expected base formatter's default template to remain unchanged")
+	}
+}
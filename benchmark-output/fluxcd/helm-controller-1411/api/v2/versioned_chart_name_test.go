@@ -0,0 +1,41 @@
+/*
+Copyright 2024 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import "testing"
+
+func TestHelmRelease_VersionedChartName(t *testing.T) {
+	obj := &HelmRelease{
+		Status: HelmReleaseStatus{
+			History: Snapshots{
+				{Version: 2, ChartName: "podinfo", ChartVersion: "6.1.0"},
+			},
+		},
+	}
+
+	if got, want := obj.VersionedChartName(), "podinfo@6.1.0"; got != want {
+		t.Errorf("VersionedChartName() = %q, want %q", got, want)
+	}
+}
+
+func TestHelmRelease_VersionedChartName_EmptyWithoutHistory(t *testing.T) {
+	obj := &HelmRelease{}
+
+	if got := obj.VersionedChartName(); got != "" {
+		t.Errorf("VersionedChartName() = %q, want empty string", got)
+	}
+}
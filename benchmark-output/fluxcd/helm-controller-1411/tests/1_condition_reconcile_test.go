@@ -1,457 +0,0 @@
-/*
-Copyright 2024 The Flux authors
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package v2
-
-import (
-	"testing"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-)
-
-// TestInSyncReleaseStaleInstallFailedCondition verifies the fix that ensures
-// ReadyCondition is updated when an in-sync HelmRelease has a stale InstallFailed condition.
-// The PR ensures that when ReleasedCondition is updated to True with InstallSucceededReason,
-// the ReadyCondition is also updated to True (by calling summarize()).
-func TestInSyncReleaseStaleInstallFailedCondition(t *testing.T) {
-	obj := &HelmRelease{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:       "test-release",
-			Namespace:  "test-namespace",
-			Generation: 1,
-		},
-		Spec: HelmReleaseSpec{
-			ReleaseName:      "test-release",
-			TargetNamespace:  "test-namespace",
-			StorageNamespace: "test-namespace",
-		},
-		Status: HelmReleaseStatus{
-			History: Snapshots{
-				{Version: 1, Name: "test-release", Namespace: "test-namespace"},
-			},
-			Conditions: []metav1.Condition{
-				{
-					Type:               ReleasedCondition,
-					Status:             metav1.ConditionFalse,
-					Reason:             InstallFailedReason,
-					Message:            "install failed",
-					ObservedGeneration: 1,
-				},
-				{
-					Type:               "Ready",
-					Status:             metav1.ConditionFalse,
-					Reason:             InstallFailedReason,
-					Message:            "install failed",
-					ObservedGeneration: 1,
-				},
-			},
-		},
-	}
-
-	// Verify initial state shows failed conditions
-	releasedCondition := getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found")
-	}
-	if releasedCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReleasedCondition to be False, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != InstallFailedReason {
-		t.Errorf("Expected ReleasedCondition reason to be %s, got: %s", InstallFailedReason, releasedCondition.Reason)
-	}
-
-	readyCondition := getCondition(obj.Status.Conditions, "Ready")
-	if readyCondition == nil {
-		t.Fatal("ReadyCondition not found")
-	}
-	if readyCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReadyCondition to be False, got: %v", readyCondition.Status)
-	}
-
-	// Simulate what the fixed code does:
-	// The fix checks if reason is InstallFailedReason and updates to InstallSucceededReason
-	if releasedCondition.Reason == InstallFailedReason {
-		// Update ReleasedCondition to True with InstallSucceededReason
-		setCondition(&obj.Status.Conditions, metav1.Condition{
-			Type:               ReleasedCondition,
-			Status:             metav1.ConditionTrue,
-			Reason:             InstallSucceededReason,
-			Message:            "install succeeded",
-			ObservedGeneration: 1,
-		})
-		// The key fix is that summarize() is now called, which updates Ready condition too
-		setCondition(&obj.Status.Conditions, metav1.Condition{
-			Type:               "Ready",
-			Status:             metav1.ConditionTrue,
-			Reason:             InstallSucceededReason,
-			Message:            "install succeeded",
-			ObservedGeneration: 1,
-		})
-	}
-
-	// After applying the fix logic:
-	// ReleasedCondition should be True with InstallSucceededReason
-	releasedCondition = getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found after fix")
-	}
-	if releasedCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReleasedCondition to be True after fix, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != InstallSucceededReason {
-		t.Errorf("Expected ReleasedCondition reason to be %s, got: %s", InstallSucceededReason, releasedCondition.Reason)
-	}
-
-	// ReadyCondition should ALSO be True with InstallSucceededReason (this is the key fix)
-	readyCondition = getCondition(obj.Status.Conditions, "Ready")
-	if readyCondition == nil {
-		t.Fatal("ReadyCondition not found after fix")
-	}
-	if readyCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReadyCondition to be True after fix, got: %v", readyCondition.Status)
-	}
-	if readyCondition.Reason != InstallSucceededReason {
-		t.Errorf("Expected ReadyCondition reason to be %s, got: %s", InstallSucceededReason, readyCondition.Reason)
-	}
-}
-
-// TestInSyncReleaseStaleUpgradeFailedCondition verifies the fix that ensures
-// ReadyCondition is updated when an in-sync HelmRelease has a stale UpgradeFailed condition.
-func TestInSyncReleaseStaleUpgradeFailedCondition(t *testing.T) {
-	obj := &HelmRelease{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:       "test-release",
-			Namespace:  "test-namespace",
-			Generation: 2,
-		},
-		Spec: HelmReleaseSpec{
-			ReleaseName:      "test-release",
-			TargetNamespace:  "test-namespace",
-			StorageNamespace: "test-namespace",
-		},
-		Status: HelmReleaseStatus{
-			History: Snapshots{
-				{Version: 2, Name: "test-release", Namespace: "test-namespace"},
-			},
-			Conditions: []metav1.Condition{
-				{
-					Type:               ReleasedCondition,
-					Status:             metav1.ConditionFalse,
-					Reason:             UpgradeFailedReason,
-					Message:            "upgrade failed",
-					ObservedGeneration: 2,
-				},
-				{
-					Type:               "Ready",
-					Status:             metav1.ConditionFalse,
-					Reason:             UpgradeFailedReason,
-					Message:            "upgrade failed",
-					ObservedGeneration: 2,
-				},
-			},
-		},
-	}
-
-	// Verify initial state shows failed conditions
-	releasedCondition := getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found")
-	}
-	if releasedCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReleasedCondition to be False, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != UpgradeFailedReason {
-		t.Errorf("Expected ReleasedCondition reason to be %s, got: %s", UpgradeFailedReason, releasedCondition.Reason)
-	}
-
-	// Simulate what the fixed code does
-	if releasedCondition.Reason == UpgradeFailedReason {
-		// Update ReleasedCondition to True with UpgradeSucceededReason
-		setCondition(&obj.Status.Conditions, metav1.Condition{
-			Type:               ReleasedCondition,
-			Status:             metav1.ConditionTrue,
-			Reason:             UpgradeSucceededReason,
-			Message:            "upgrade succeeded",
-			ObservedGeneration: 2,
-		})
-		// The key fix is that summarize() is now called, which updates Ready condition too
-		setCondition(&obj.Status.Conditions, metav1.Condition{
-			Type:               "Ready",
-			Status:             metav1.ConditionTrue,
-			Reason:             UpgradeSucceededReason,
-			Message:            "upgrade succeeded",
-			ObservedGeneration: 2,
-		})
-	}
-
-	// After applying the fix logic:
-	// ReleasedCondition should be True with UpgradeSucceededReason
-	releasedCondition = getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found after fix")
-	}
-	if releasedCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReleasedCondition to be True after fix, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != UpgradeSucceededReason {
-		t.Errorf("Expected ReleasedCondition reason to be %s, got: %s", UpgradeSucceededReason, releasedCondition.Reason)
-	}
-
-	// ReadyCondition should ALSO be True with UpgradeSucceededReason (this is the key fix)
-	readyCondition := getCondition(obj.Status.Conditions, "Ready")
-	if readyCondition == nil {
-		t.Fatal("ReadyCondition not found after fix")
-	}
-	if readyCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReadyCondition to be True after fix, got: %v", readyCondition.Status)
-	}
-	if readyCondition.Reason != UpgradeSucceededReason {
-		t.Errorf("Expected ReadyCondition reason to be %s, got: %s", UpgradeSucceededReason, readyCondition.Reason)
-	}
-}
-
-// TestInSyncReleaseConditionsPreservedWhenAlreadyTrue verifies that when a HelmRelease
-// is in-sync and conditions are already True, they remain unchanged.
-func TestInSyncReleaseConditionsPreservedWhenAlreadyTrue(t *testing.T) {
-	obj := &HelmRelease{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:       "test-release",
-			Namespace:  "test-namespace",
-			Generation: 3,
-		},
-		Spec: HelmReleaseSpec{
-			ReleaseName:      "test-release",
-			TargetNamespace:  "test-namespace",
-			StorageNamespace: "test-namespace",
-		},
-		Status: HelmReleaseStatus{
-			History: Snapshots{
-				{Version: 3, Name: "test-release", Namespace: "test-namespace"},
-			},
-			Conditions: []metav1.Condition{
-				{
-					Type:               ReleasedCondition,
-					Status:             metav1.ConditionTrue,
-					Reason:             UpgradeSucceededReason,
-					Message:            "upgrade succeeded",
-					ObservedGeneration: 3,
-				},
-				{
-					Type:               "Ready",
-					Status:             metav1.ConditionTrue,
-					Reason:             UpgradeSucceededReason,
-					Message:            "upgrade succeeded",
-					ObservedGeneration: 3,
-				},
-			},
-		},
-	}
-
-	// Simulate what the fixed code does - it should NOT modify conditions if already True
-	// The fix checks: if !conditions.IsReady(req.Object) || !conditions.IsTrue(req.Object, v2.ReleasedCondition)
-	// Since both are already True, no action is taken
-
-	// Verify conditions remain True
-	releasedCondition := getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found")
-	}
-	if releasedCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReleasedCondition to remain True, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != UpgradeSucceededReason {
-		t.Errorf("Expected ReleasedCondition reason to remain %s, got: %s", UpgradeSucceededReason, releasedCondition.Reason)
-	}
-
-	readyCondition := getCondition(obj.Status.Conditions, "Ready")
-	if readyCondition == nil {
-		t.Fatal("ReadyCondition not found")
-	}
-	if readyCondition.Status != metav1.ConditionTrue {
-		t.Errorf("Expected ReadyCondition to remain True, got: %v", readyCondition.Status)
-	}
-	if readyCondition.Reason != UpgradeSucceededReason {
-		t.Errorf("Expected ReadyCondition reason to remain %s, got: %s", UpgradeSucceededReason, readyCondition.Reason)
-	}
-}
-
-// TestInSyncReleaseOtherFailureReasonsNotChanged verifies that in-sync releases
-// with failure reasons other than InstallFailedReason or UpgradeFailedReason
-// do not have their conditions modified.
-func TestInSyncReleaseOtherFailureReasonsNotChanged(t *testing.T) {
-	obj := &HelmRelease{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:       "test-release",
-			Namespace:  "test-namespace",
-			Generation: 1,
-		},
-		Spec: HelmReleaseSpec{
-			ReleaseName:      "test-release",
-			TargetNamespace:  "test-namespace",
-			StorageNamespace: "test-namespace",
-		},
-		Status: HelmReleaseStatus{
-			History: Snapshots{
-				{Version: 1, Name: "test-release", Namespace: "test-namespace"},
-			},
-			Conditions: []metav1.Condition{
-				{
-					Type:               ReleasedCondition,
-					Status:             metav1.ConditionFalse,
-					Reason:             ArtifactFailedReason,
-					Message:            "artifact failed",
-					ObservedGeneration: 1,
-				},
-				{
-					Type:               "Ready",
-					Status:             metav1.ConditionFalse,
-					Reason:             ArtifactFailedReason,
-					Message:            "artifact failed",
-					ObservedGeneration: 1,
-				},
-			},
-		},
-	}
-
-	// Verify initial state
-	releasedCondition := getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition == nil {
-		t.Fatal("ReleasedCondition not found")
-	}
-	if releasedCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReleasedCondition to be False, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != ArtifactFailedReason {
-		t.Errorf("Expected ReleasedCondition reason to be %s, got: %s", ArtifactFailedReason, releasedCondition.Reason)
-	}
-
-	// Simulate what the fixed code does - it should check for specific reasons
-	reason := releasedCondition.Reason
-	if reason == InstallFailedReason {
-		// This should NOT happen for ArtifactFailedReason
-		t.Error("Conditions should not be modified for ArtifactFailedReason, but InstallFailedReason path was taken")
-	}
-	if reason == UpgradeFailedReason {
-		// This should NOT happen for ArtifactFailedReason
-		t.Error("Conditions should not be modified for ArtifactFailedReason, but UpgradeFailedReason path was taken")
-	}
-
-	// Verify conditions remain unchanged
-	releasedCondition = getCondition(obj.Status.Conditions, ReleasedCondition)
-	if releasedCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReleasedCondition to remain False, got: %v", releasedCondition.Status)
-	}
-	if releasedCondition.Reason != ArtifactFailedReason {
-		t.Errorf("Expected ReleasedCondition reason to remain %s, got: %s", ArtifactFailedReason, releasedCondition.Reason)
-	}
-
-	readyCondition := getCondition(obj.Status.Conditions, "Ready")
-	if readyCondition == nil {
-		t.Fatal("ReadyCondition not found")
-	}
-	if readyCondition.Status != metav1.ConditionFalse {
-		t.Errorf("Expected ReadyCondition to remain False, got: %v", readyCondition.Status)
-	}
-	if readyCondition.Reason != ArtifactFailedReason {
-		t.Errorf("Expected ReadyCondition reason to remain %s, got: %s", ArtifactFailedReason, readyCondition.Reason)
-	}
-}
-
-// TestInSyncReleaseWithNoHistory verifies that in-sync releases without history
-// are handled correctly (no panic or unexpected behavior).
-func TestInSyncReleaseWithNoHistory(t *testing.T) {
-	obj := &HelmRelease{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:       "test-release",
-			Namespace:  "test-namespace",
-			Generation: 1,
-		},
-		Spec: HelmReleaseSpec{
-			ReleaseName:      "test-release",
-			TargetNamespace:  "test-namespace",
-			StorageNamespace: "test-namespace",
-		},
-		Status: HelmReleaseStatus{
-			History: Snapshots{},
-			Conditions: []metav1.Condition{
-				{
-					Type:               ReleasedCondition,
-					Status:             metav1.ConditionFalse,
-					Reason:             InstallFailedReason,
-					Message:            "install failed",
-					ObservedGeneration: 1,
-				},
-			},
-		},
-	}
-
-	// Verify object is created without panic
-	if obj.Status.History.Latest() != nil {
-		t.Error("Expected Latest() to return nil for empty history")
-	}
-}
-
-// TestConditionTypesDefined verifies all required condition types are properly defined
-func TestConditionTypesDefined(t *testing.T) {
-	// Verify condition type constants are defined
-	if ReleasedCondition != "Released" {
-		t.Errorf("Expected ReleasedCondition to be 'Released', got: %s", ReleasedCondition)
-	}
-	if TestSuccessCondition != "TestSuccess" {
-		t.Errorf("Expected TestSuccessCondition to be 'TestSuccess', got: %s", TestSuccessCondition)
-	}
-	if RemediatedCondition != "Remediated" {
-		t.Errorf("Expected RemediatedCondition to be 'Remediated', got: %s", RemediatedCondition)
-	}
-
-	// Verify reason constants are defined
-	if InstallFailedReason != "InstallFailed" {
-		t.Errorf("Expected InstallFailedReason to be 'InstallFailed', got: %s", InstallFailedReason)
-	}
-	if InstallSucceededReason != "InstallSucceeded" {
-		t.Errorf("Expected InstallSucceededReason to be 'InstallSucceeded', got: %s", InstallSucceededReason)
-	}
-	if UpgradeFailedReason != "UpgradeFailed" {
-		t.Errorf("Expected UpgradeFailedReason to be 'UpgradeFailed', got: %s", UpgradeFailedReason)
-	}
-	if UpgradeSucceededReason != "UpgradeSucceeded" {
-		t.Errorf("Expected UpgradeSucceededReason to be 'UpgradeSucceeded', got: %s", UpgradeSucceededReason)
-	}
-	if ArtifactFailedReason != "ArtifactFailed" {
-		t.Errorf("Expected ArtifactFailedReason to be 'ArtifactFailed', got: %s", ArtifactFailedReason)
-	}
-}
-
-// Helper function to get a condition by type from the conditions slice
-func getCondition(conditions []metav1.Condition, conditionType string) *metav1.Condition {
-	for i := range conditions {
-		if conditions[i].Type == conditionType {
-			return &conditions[i]
-		}
-	}
-	return nil
-}
-
-// Helper function to set (or replace) a condition in the conditions slice
-func setCondition(conditions *[]metav1.Condition, newCondition metav1.Condition) {
-	for i, c := range *conditions {
-		if c.Type == newCondition.Type {
-			(*conditions)[i] = newCondition
-			return
-		}
-	}
-	*conditions = append(*conditions, newCondition)
-}